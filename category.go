@@ -0,0 +1,153 @@
+package speakerdeck
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
+)
+
+// DefaultCategoryMaxPages is how many pages of a category are scraped, unless opts.MaxPages is set.
+// Categories often list thousands of talks, so they are never scraped in full by default
+const DefaultCategoryMaxPages = 5
+
+// ScrapeCategory returns the talks listed in the given category, e.g. "technology" or "Technology".
+// ScrapeCategory uses DefaultClient, see Client.ScrapeCategory.
+func ScrapeCategory(category string, opts *scraper.ScrapeOptions) (*Category, error) {
+	return DefaultClient.ScrapeCategory(category, opts)
+}
+
+// ScrapeCategoryContext works like ScrapeCategory, but stops scraping when ctx is done.
+// ScrapeCategoryContext uses DefaultClient, see Client.ScrapeCategoryContext.
+func ScrapeCategoryContext(ctx context.Context, category string, opts *scraper.ScrapeOptions) (*Category, error) {
+	return DefaultClient.ScrapeCategoryContext(ctx, category, opts)
+}
+
+// ScrapeCategory returns the talks listed in the given category, e.g. "technology" or "Technology".
+// At most opts.MaxPages pages of the category are scraped, or DefaultCategoryMaxPages if not set.
+func (c *Client) ScrapeCategory(category string, opts *scraper.ScrapeOptions) (*Category, error) {
+	return c.ScrapeCategoryContext(context.Background(), category, opts)
+}
+
+// ScrapeCategoryContext works like ScrapeCategory, but stops scraping when ctx is done. No further
+// pages of the category are visited after ctx is cancelled.
+func (c *Client) ScrapeCategoryContext(ctx context.Context, category string, opts *scraper.ScrapeOptions) (*Category, error) {
+	slug := CategorySlug(category)
+	if len(slug) == 0 {
+		return nil, fmt.Errorf("category is mandatory!")
+	}
+
+	opts = c.scrapeOptions(opts)
+	if opts.MaxPages == 0 {
+		opts.MaxPages = DefaultCategoryMaxPages
+	}
+	cat, err := typed.Scrape[Category](ctx, c.url("c", slug), (&CategoryScraper{}).Typed(), opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &CategoryError{Category: slug, Err: err}
+	}
+	return cat, nil
+}
+
+// CategorySlug returns the URL-encoded form of a category, e.g. "machine-learning" for "Machine Learning"
+func CategorySlug(category string) string {
+	return strings.Join(strings.Fields(strings.ToLower(category)), "-")
+}
+
+var _ scraper.Scraper = &CategoryScraper{}
+
+// CategoryScraper implements scraper.Scraper. Its typed.Scraper[Category] is returned by Typed
+type CategoryScraper struct{}
+
+// Name returns the name of the CategoryScraper
+func (s *CategoryScraper) Name() string {
+	return "CategoryScraper"
+}
+
+// Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
+// of them
+func (s *CategoryScraper) Hooks() []scraper.Hook {
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *CategoryScraper) InitialData() interface{} {
+	return NewCategory()
+}
+
+// Typed returns the CategoryScraper as a typed.Scraper[Category], whose handlers get the *Category
+// passed directly
+func (s *CategoryScraper) Typed() typed.Scraper[Category] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewCategory)
+}
+
+func (s *CategoryScraper) typedHooks() []typed.Hook[Category] {
+	return []typed.Hook[Category]{
+		{
+			DOMPath:    ".sd-main .container h1",
+			Handler:    onCategoryName,
+			Required:   true,
+			MaxMatches: 1,
+		},
+		{
+			DOMPath: ".container .row > div:has(a[href][title])",
+			Handler: onCategoryTalkFound,
+		},
+		{
+			DOMPath:    ".next .page-link[rel='next']",
+			Handler:    onNextPage[Category],
+			MaxMatches: 1,
+		},
+	}
+}
+
+func onCategoryName(e *colly.HTMLElement, c *Category) (*string, error) {
+	// The name and link are the same on all pages, take them from the first one
+	if len(c.Link) == 0 {
+		c.Name = strings.TrimSpace(e.Text)
+		c.Link = e.Request.URL.String()
+	}
+	return nil, nil
+}
+
+func onCategoryTalkFound(e *colly.HTMLElement, c *Category) (*string, error) {
+	t, err := parseAuthoredTalkPreview(e)
+	if err != nil {
+		return nil, err
+	}
+	c.TalkPreviews = append(c.TalkPreviews, *t)
+	return nil, nil
+}
+
+// parseAuthoredTalkPreview parses the preview card of a talk together with its author, as listed on
+// e.g. category and search pages. e is the element containing both the talk and the author links
+func parseAuthoredTalkPreview(e *colly.HTMLElement) (*AuthoredTalkPreview, error) {
+	var err error
+	t := &AuthoredTalkPreview{}
+	e.ForEachWithBreak("a[href][title]", func(_ int, a *colly.HTMLElement) bool {
+		var preview *TalkPreview
+		if preview, err = parseTalkPreview(a); err == nil {
+			t.TalkPreview = *preview
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authorLink := e.ChildAttr("a.deck-author", "href")
+	if len(authorLink) > 0 {
+		t.Author.Link = e.Request.AbsoluteURL(authorLink)
+		t.Author.Handle = path.Base(t.Author.Link)
+		t.Author.Name = strings.TrimSpace(e.ChildText("a.deck-author"))
+		t.Author.AvatarLink = httpsPrefix + e.ChildAttr("a.deck-author img", "src")
+	}
+	return t, nil
+}
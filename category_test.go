@@ -0,0 +1,201 @@
+package speakerdeck_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+// listedTalk is a fixture talk together with its author, as listed on category and search pages
+type listedTalk struct {
+	user *speakerdecktest.User
+	talk speakerdecktest.Talk
+}
+
+// categoryTalks returns the talks of users in category, newest first
+func categoryTalks(users []speakerdecktest.User, category string) []listedTalk {
+	return listedTalks(users, func(t speakerdecktest.Talk) bool {
+		return t.Category == category
+	})
+}
+
+// listedTalks returns the talks of users matching match, newest first, as the fake server lists them
+func listedTalks(users []speakerdecktest.User, match func(speakerdecktest.Talk) bool) []listedTalk {
+	sorted := make([]*speakerdecktest.User, 0, len(users))
+	for i := range users {
+		sorted = append(sorted, &users[i])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Handle < sorted[j].Handle
+	})
+
+	talks := []listedTalk{}
+	for _, u := range sorted {
+		for _, t := range u.Talks {
+			if match(t) {
+				talks = append(talks, listedTalk{user: u, talk: t})
+			}
+		}
+	}
+	sort.SliceStable(talks, func(i, j int) bool {
+		return talks[i].talk.Date.After(talks[j].talk.Date)
+	})
+	return talks
+}
+
+// checkAuthoredTalkPreviews compares the scraped previews with the expected fixture talks, in order
+func checkAuthoredTalkPreviews(t *testing.T, baseURL string, got []speakerdeck.AuthoredTalkPreview, want []listedTalk) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d talks, want %d", len(got), len(want))
+	}
+	for i, preview := range got {
+		w := want[i]
+		if preview.ID != w.talk.ID || preview.Title != w.talk.Title || preview.DataID != w.talk.DataID {
+			t.Errorf("talk %d = %q (%q), want %q (%q)", i, preview.ID, preview.Title, w.talk.ID, w.talk.Title)
+		}
+		if link := fmt.Sprintf("%s/%s/%s", baseURL, w.user.Handle, w.talk.ID); preview.Link != link {
+			t.Errorf("talk %d has link %q, want %q", i, preview.Link, link)
+		}
+		if preview.Stars != w.talk.Stars || preview.Views != w.talk.Views {
+			t.Errorf("talk %q has %d stars and %d views, want %d and %d", preview.ID, preview.Stars, preview.Views, w.talk.Stars, w.talk.Views)
+		}
+		wantAuthor := speakerdeck.Author{
+			Name:       w.user.Name,
+			Handle:     w.user.Handle,
+			Link:       baseURL + "/" + w.user.Handle,
+			AvatarLink: "https:" + w.user.AvatarLink,
+		}
+		if preview.Author != wantAuthor {
+			t.Errorf("talk %q has author %+v, want %+v", preview.ID, preview.Author, wantAuthor)
+		}
+	}
+}
+
+// manyTalks returns a user with n talks in the "Technology" category
+func manyTalks(n int) speakerdecktest.User {
+	u := speakerdecktest.User{Name: "Busy Speaker", Handle: "busy", AvatarLink: "//secure.gravatar.com/avatar/busy"}
+	for i := 0; i < n; i++ {
+		u.Talks = append(u.Talks, speakerdecktest.Talk{
+			ID:       fmt.Sprintf("talk-%d", i),
+			Title:    fmt.Sprintf("Talk %d", i),
+			DataID:   fmt.Sprintf("%032d", i),
+			Date:     time.Date(2020, time.January, 1+i, 0, 0, 0, 0, time.UTC),
+			Category: "Technology",
+		})
+	}
+	return u
+}
+
+func TestScrapeCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    []speakerdecktest.User
+		category string
+		pageSize int
+		maxPages int
+		// wantTalks is how many of the talks in the category are expected, newest first
+		wantTalks int
+		wantPages int
+		wantName  string
+		wantErr   error
+	}{
+		{
+			name:      "paginated category",
+			category:  "technology",
+			wantTalks: 5,
+			wantPages: 3,
+			wantName:  "Technology",
+		},
+		{
+			name:      "category name",
+			category:  "Technology",
+			wantTalks: 5,
+			wantPages: 3,
+			wantName:  "Technology",
+		},
+		{
+			name:      "one page",
+			category:  "programming",
+			wantTalks: 1,
+			wantPages: 1,
+			wantName:  "Programming",
+		},
+		{
+			name:      "MaxPages",
+			category:  "technology",
+			maxPages:  2,
+			wantTalks: 4,
+			wantPages: 2,
+			wantName:  "Technology",
+		},
+		{
+			name:      "DefaultCategoryMaxPages",
+			users:     []speakerdecktest.User{manyTalks(speakerdeck.DefaultCategoryMaxPages + 2)},
+			category:  "technology",
+			pageSize:  1,
+			wantTalks: speakerdeck.DefaultCategoryMaxPages,
+			wantPages: speakerdeck.DefaultCategoryMaxPages,
+			wantName:  "Technology",
+		},
+		{
+			name:      "unknown category",
+			category:  "machine-learning",
+			wantPages: 1,
+			wantErr:   speakerdeck.ErrCategoryNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := tt.users
+			if users == nil {
+				users = speakerdecktest.DefaultUsers()
+			}
+			s := speakerdecktest.NewServer(users...)
+			defer s.Close()
+			if tt.pageSize > 0 {
+				s.PageSize = tt.pageSize
+			}
+
+			cat, err := s.NewClient().ScrapeCategory(tt.category, &scraper.ScrapeOptions{MaxPages: tt.maxPages})
+			if got := s.Requests("/c/" + speakerdeck.CategorySlug(tt.category)); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScrapeCategory() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScrapeCategory() error = %v", err)
+			}
+
+			if link := s.URL + "/c/" + speakerdeck.CategorySlug(tt.category); cat.Name != tt.wantName || cat.Link != link {
+				t.Errorf("category = %q (%q), want %q (%q)", cat.Name, cat.Link, tt.wantName, link)
+			}
+			checkAuthoredTalkPreviews(t, s.URL, cat.TalkPreviews, categoryTalks(users, tt.wantName)[:tt.wantTalks])
+		})
+	}
+}
+
+func TestCategorySlug(t *testing.T) {
+	tests := map[string]string{
+		"technology":         "technology",
+		"Technology":         "technology",
+		"Machine Learning":   "machine-learning",
+		"  Science  Nature ": "science-nature",
+		"":                   "",
+	}
+	for category, want := range tests {
+		if got := speakerdeck.CategorySlug(category); got != want {
+			t.Errorf("CategorySlug(%q) = %q, want %q", category, got, want)
+		}
+	}
+}
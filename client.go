@@ -0,0 +1,75 @@
+package speakerdeck
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/luxas/speakerdeck-api/scraper"
+)
+
+// DefaultBaseURL is the root URL of Speakerdeck, used unless a Client specifies otherwise
+const DefaultBaseURL = "https://speakerdeck.com"
+
+// DefaultClient is the Client used by the package-level scrape functions, e.g. ScrapeUser and ScrapeTalks
+var DefaultClient = NewClient()
+
+// NewClient creates a new Client scraping the pages at DefaultBaseURL
+func NewClient() *Client {
+	return &Client{
+		BaseURL: DefaultBaseURL,
+	}
+}
+
+// Client scrapes Speakerdeck pages using a given base URL and HTTP settings. The settings
+// of the Client are threaded into the scraper.ScrapeOptions of every scrape, unless the
+// ScrapeOptions passed to the scrape function already specify them.
+type Client struct {
+	// BaseURL is the root URL to scrape pages from. This can e.g. point to a local mirror,
+	// a recording proxy or an httptest.Server. If empty, DefaultBaseURL is used
+	BaseURL string
+
+	// Transport specifies the http.RoundTripper used for all requests. If nil, http.DefaultTransport is used
+	Transport http.RoundTripper
+
+	// UserAgent overrides the User-Agent header sent with all requests, if set
+	UserAgent string
+
+	// Timeout overrides the default request timeout, if set
+	Timeout time.Duration
+
+	// MetadataKeys are the "Key: value" metadata keys recognised in talk descriptions, and
+	// set in Talk.Metadata. If nil, DefaultMetadataKeys is used
+	MetadataKeys []string
+
+	// VideoRecognizers recognise the links to recordings in talk descriptions, which are set in
+	// Talk.Videos. If nil, DefaultVideoRecognizers is used
+	VideoRecognizers []VideoRecognizer
+}
+
+// url returns the absolute URL for the given path elements, relative to the BaseURL
+func (c *Client) url(elems ...string) string {
+	baseURL := c.BaseURL
+	if len(baseURL) == 0 {
+		baseURL = DefaultBaseURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.Join(elems, "/")
+}
+
+// scrapeOptions returns a copy of opts, with the Client settings set where opts doesn't specify them
+func (c *Client) scrapeOptions(opts *scraper.ScrapeOptions) *scraper.ScrapeOptions {
+	o := scraper.ScrapeOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Transport == nil {
+		o.Transport = c.Transport
+	}
+	if len(o.UserAgent) == 0 {
+		o.UserAgent = c.UserAgent
+	}
+	if o.Timeout == 0 {
+		o.Timeout = c.Timeout
+	}
+	return &o
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,8 +28,15 @@ const (
 <span>Available paths are:</span>
 <ul>
 	<li>/api/users/{user-handle}</li>
-	<li>/api/talks/{user-handle}</li>
-	<li>/api/talks/{user-handle}/{talk-id}</li>
+	<li>/api/users/{user-handle}/stars</li>
+	<li>/api/users/{user-handle}/followers</li>
+	<li>/api/users/{user-handle}/following</li>
+	<li>/api/graph/{user-handle}?depth={depth}&amp;format={json|graphml}</li>
+	<li>/api/talks/{user-handle}?slides={true|false}</li>
+	<li>/api/talks/{user-handle}/{talk-id}?slides={true|false}</li>
+	<li>/api/talks/{user-handle}/{talk-id}/slides</li>
+	<li>/api/categories/{category}?max-pages={pages}</li>
+	<li>/api/search?q={query}&amp;max-pages={pages}&amp;stream={true|false}</li>
 </ul>
 <br />
 <span>Created by Lucas Käldström. Source code at: <a href="https://github.com/luxas/speakerdeck-api">github.com/luxas/speakerdeck-api</a></span>
@@ -32,33 +44,129 @@ const (
 )
 
 var (
-	validPaths = regexp.MustCompile(`^` + prefix + `/(talks|users)/([a-zA-Z0-9/-]+)$`)
+	validPaths = regexp.MustCompile(`^` + prefix + `/(?:(?:talks|users|categories|graph)/([a-zA-Z0-9/-]+)|search)$`)
 
 	address    = flag.String("address", "0.0.0.0", "What address to expose the API on")
 	port       = flag.Int("port", 8080, "What port to expose the API on")
 	mapsAPIKey = flag.String("maps-api-key", "", "Google Maps API key with the Geocoding API usage set")
+	geocoder   = flag.String("geocoder", "", "What geocoder to resolve talk locations with: google, nominatim or gazetteer. Defaults to google if -maps-api-key is set, otherwise locations aren't resolved")
+	nominatim  = flag.String("nominatim-endpoint", location.DefaultNominatimEndpoint, "The search endpoint of the Nominatim-compatible API used by the nominatim geocoder")
+	gazetteers = flag.String("gazetteer-files", "", "Comma-separated list of GeoNames (.txt) and venue (.csv) files loaded by the gazetteer geocoder, and used for resolving the time zones of talk locations")
+	geoCache   = flag.String("geocode-cache-file", "", "Where to cache geocoded talk locations on disk, as JSON. If empty, locations are cached in memory")
+	geoTTL     = flag.Duration("geocode-cache-ttl", location.DefaultGeocodeTTL, "For how long to use a geocoded talk location before geocoding it again")
+	geoNegTTL  = flag.Duration("geocode-negative-ttl", location.DefaultNegativeGeocodeTTL, "For how long to remember that a talk location could not be geocoded. Negative disables it")
+	geoPinned  = flag.String("geocode-overrides", "", "JSON file mapping talk locations to pinned results, for places the geocoder gets wrong")
+	baseURL    = flag.String("base-url", speakerdeck.DefaultBaseURL, "What Speakerdeck URL to scrape, e.g. a local mirror")
+	userAgent  = flag.String("user-agent", "", "What User-Agent to use when scraping Speakerdeck")
+	maxConc    = flag.Int("max-concurrency", scraper.DefaultMaxConcurrency, "How many pages to fetch from Speakerdeck at the same time")
+	rps        = flag.Float64("requests-per-second", 0, "How many requests per second to make to Speakerdeck at most, 0 means unlimited")
+	burst      = flag.Int("burst", 1, "How many requests to Speakerdeck may be made at once before being rate limited")
+	attempts   = flag.Int("max-attempts", scraper.DefaultMaxAttempts, "How many times to try fetching a page from Speakerdeck before giving up")
+	cacheDir   = flag.String("http-cache-dir", "", "Where to cache fetched Speakerdeck pages on disk. If empty, pages are cached in memory")
+	cacheAge   = flag.Duration("http-cache-max-age", 0, "For how long to use a cached Speakerdeck page without checking if it has changed")
+	resultTTL  = flag.Duration("cache-ttl", 5*time.Minute, "For how long to serve scraped users and talks without scraping them again. 0 disables caching")
+	staleTTL   = flag.Duration("cache-stale-ttl", time.Hour, "For how long to serve expired users and talks while they are scraped again in the background")
+	maxCached  = flag.Int("cache-max-entries", 1000, "How many scraped results to cache at most. 0 means unlimited")
+	watch      = flag.String("watch", "", "Comma-separated list of user handles, whose users and talks are kept cached by refreshing them in the background")
+	refresh    = flag.Duration("refresh-interval", 5*time.Minute, "How often to refresh the users and talks of the watched handles")
+	videoHosts = flag.String("video-hosts", "", "Comma-separated list of extra hosts, e.g. media.ccc.de, whose links in talk descriptions are recordings of the talk")
+	maxDepth   = flag.Int("max-graph-depth", 2, "How deep social graphs may be crawled from the requested user")
 
+	client      *speakerdeck.Client
+	rateLimit   *scraper.RateLimit
+	retryPolicy *scraper.RetryPolicy
+	cache       *scraper.ResponseCache
+	results     *resultCache
+	gazetteer   *location.Gazetteer
 	locationExt *location.LocationExtension
 )
 
 func main() {
 	flag.Parse()
-	http.HandleFunc("/", makeHandler(helpHandler))
-	http.HandleFunc(prefix+"/users/", makeHandler(usersHandler))
-	http.HandleFunc(prefix+"/talks/", makeHandler(talksHandler))
+	client = &speakerdeck.Client{
+		BaseURL:   *baseURL,
+		UserAgent: *userAgent,
+	}
+	if len(*videoHosts) > 0 {
+		client.VideoRecognizers = append([]speakerdeck.VideoRecognizer{}, speakerdeck.DefaultVideoRecognizers...)
+		for _, host := range strings.Split(*videoHosts, ",") {
+			host = strings.TrimSpace(host)
+			client.VideoRecognizers = append(client.VideoRecognizers, &speakerdeck.HostRecognizer{Provider: host, Hosts: []string{host}})
+		}
+	}
+	// The rate limit is shared between all requests to the API
+	rateLimit = &scraper.RateLimit{
+		MaxConcurrency:    *maxConc,
+		RequestsPerSecond: *rps,
+		Burst:             *burst,
+	}
+	retryPolicy = &scraper.RetryPolicy{
+		MaxAttempts: *attempts,
+		Jitter:      0.2,
+	}
+	cache = &scraper.ResponseCache{
+		Store:  scraper.NewMemoryStore(1000),
+		MaxAge: *cacheAge,
+	}
+	if len(*cacheDir) > 0 {
+		cache.Store = scraper.NewDiskStore(*cacheDir)
+	}
+
+	if flag.Arg(0) == "selfcheck" {
+		os.Exit(runSelfcheck(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
 
-	if len(*mapsAPIKey) > 0 {
+	if len(*gazetteers) > 0 {
 		var err error
-		locationExt, err = location.NewLocationExtension(*mapsAPIKey)
+		gazetteer, err = location.LoadGazetteer(strings.Split(*gazetteers, ",")...)
 		if err != nil {
 			log.Fatal(err)
 		}
+		log.Printf("Loaded %d places into the gazetteer", gazetteer.Len())
+	}
+	g, err := newGeocoder()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if g != nil {
+		g, err = newCachingGeocoder(g)
+		if err != nil {
+			log.Fatal(err)
+		}
+		locationExt = location.NewLocationExtension(g)
+		if gazetteer != nil {
+			locationExt.Timezones = gazetteer
+		}
 		log.Printf("Initialized the LocationExtension!")
 	}
 
+	results = newResultCache(*resultTTL, *staleTTL, *maxCached)
+	if len(*watch) > 0 {
+		fetches := map[string]fetchFunc{}
+		for _, handle := range strings.Split(*watch, ",") {
+			handle = strings.TrimSpace(handle)
+			fetches["users/"+handle] = fetchUser(handle)
+			fetches["talks/"+handle] = fetchTalks(handle, "", false)
+		}
+		go results.watch(context.Background(), *refresh, fetches)
+		log.Printf("Refreshing watched handles %s every %s", *watch, *refresh)
+	}
+
 	addrPort := fmt.Sprintf("%s:%d", *address, *port)
 	log.Printf("Starting Speakerdeck API on %s...", addrPort)
-	log.Fatal(http.ListenAndServe(addrPort, nil))
+	log.Fatal(http.ListenAndServe(addrPort, newServeMux()))
+}
+
+// newServeMux returns the handler serving the API
+func newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", makeHandler(helpHandler))
+	mux.HandleFunc(prefix+"/users/", makeHandler(usersHandler))
+	mux.HandleFunc(prefix+"/talks/", makeHandler(talksHandler))
+	mux.HandleFunc(prefix+"/categories/", makeHandler(categoriesHandler))
+	mux.HandleFunc(prefix+"/search", makeHandler(searchHandler))
+	mux.HandleFunc(prefix+"/graph/", makeHandler(graphHandler))
+	return mux
 }
 
 func makeHandler(fn func(http.ResponseWriter, *http.Request, string) (int, error)) http.HandlerFunc {
@@ -73,7 +181,7 @@ func makeHandler(fn func(http.ResponseWriter, *http.Request, string) (int, error
 			return
 		}
 		t := time.Now()
-		code, err := fn(w, r, m[2])
+		code, err := fn(w, r, m[1])
 		if err != nil {
 			http.Error(w, err.Error(), code)
 		} else {
@@ -89,19 +197,156 @@ func encodeJSON(w io.Writer, data interface{}) error {
 	return e.Encode(data)
 }
 
+// newGeocoder returns the geocoder selected by the -geocoder flag, or nil if no geocoder should be used
+func newGeocoder() (location.Geocoder, error) {
+	name := *geocoder
+	if len(name) == 0 && len(*mapsAPIKey) > 0 {
+		name = "google"
+	}
+	switch name {
+	case "":
+		return nil, nil
+	case "google":
+		if len(*mapsAPIKey) == 0 {
+			return nil, fmt.Errorf("the google geocoder requires -maps-api-key to be set")
+		}
+		return location.NewGoogleGeocoder(*mapsAPIKey)
+	case "nominatim":
+		return location.NewNominatimGeocoder(*nominatim), nil
+	case "gazetteer":
+		if gazetteer == nil {
+			return nil, fmt.Errorf("the gazetteer geocoder requires -gazetteer-files to be set")
+		}
+		return gazetteer, nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q, must be google, nominatim or gazetteer", name)
+	}
+}
+
+// newCachingGeocoder wraps g in a cache configured by the -geocode-* flags
+func newCachingGeocoder(g location.Geocoder) (location.Geocoder, error) {
+	store := location.NewMemoryGeocodeStore(0)
+	if len(*geoCache) > 0 {
+		var err error
+		if store, err = location.NewFileGeocodeStore(*geoCache); err != nil {
+			return nil, err
+		}
+	}
+	cg := location.NewCachingGeocoder(g, store)
+	cg.TTL = *geoTTL
+	cg.NegativeTTL = *geoNegTTL
+	if len(*geoPinned) > 0 {
+		if err := cg.LoadOverrides(*geoPinned); err != nil {
+			return nil, err
+		}
+	}
+	return cg, nil
+}
+
+// errorStatusCode maps scraping errors to the HTTP status code the API should respond with
+func errorStatusCode(err error) int {
+	var parseErr *scraper.ParseError
+	var noMatchErr *scraper.NoMatchError
+	switch {
+	case errors.Is(err, scraper.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scraper.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, scraper.ErrUpstreamUnavailable):
+		return http.StatusBadGateway
+	case errors.As(err, &parseErr), errors.As(err, &noMatchErr):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func scrapeOptions() *scraper.ScrapeOptions {
+	return &scraper.ScrapeOptions{
+		RateLimit: rateLimit,
+		Retry:     retryPolicy,
+		Cache:     cache,
+	}
+}
+
+func fetchUser(userID string) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return client.ScrapeUserContext(ctx, userID, scrapeOptions())
+	}
+}
+
+func fetchStars(userID string) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return client.ScrapeStarsContext(ctx, userID, scrapeOptions())
+	}
+}
+
+func fetchConnections(userID, list string) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		if list == "followers" {
+			return client.ScrapeFollowersContext(ctx, userID, scrapeOptions())
+		}
+		return client.ScrapeFollowingContext(ctx, userID, scrapeOptions())
+	}
+}
+
+func fetchGraph(userID string, depth int) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return client.ScrapeGraphContext(ctx, userID, depth, scrapeOptions())
+	}
+}
+
+func fetchTalks(userID, talkID string, slides bool) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		opts := scrapeOptions()
+		if locationExt != nil {
+			opts.Extensions = append(opts.Extensions, locationExt)
+		}
+		if slides {
+			opts.Extensions = append(opts.Extensions, &speakerdeck.SlidesExtension{})
+		}
+		return client.ScrapeTalksContext(ctx, userID, talkID, opts)
+	}
+}
+
+func fetchCategory(category string, maxPages int) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		opts := scrapeOptions()
+		opts.MaxPages = maxPages
+		return client.ScrapeCategoryContext(ctx, category, opts)
+	}
+}
+
+func fetchSearch(query string, maxPages int) fetchFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		opts := scrapeOptions()
+		opts.MaxPages = maxPages
+		return client.ScrapeSearchContext(ctx, query, opts)
+	}
+}
+
 func helpHandler(w http.ResponseWriter, r *http.Request, _ string) (int, error) {
 	w.Write([]byte(welcomeText))
 	return http.StatusOK, nil
 }
 
 func usersHandler(w http.ResponseWriter, r *http.Request, userID string) (int, error) {
-	if strings.Contains(userID, "/") {
-		return http.StatusBadRequest, fmt.Errorf("invalid user name, can't contain /")
+	if handle, sub, ok := strings.Cut(userID, "/"); ok {
+		switch {
+		case len(handle) == 0:
+		case sub == "stars":
+			return starsHandler(w, r, handle)
+		case sub == "followers" || sub == "following":
+			return connectionsHandler(w, r, handle, sub)
+		}
+		return http.StatusBadRequest, fmt.Errorf("invalid user name, argument should be of form {user}, {user}/stars, {user}/followers or {user}/following")
 	}
 
-	user, err := speakerdeck.ScrapeUser(userID, nil)
+	user, err := results.get(r.Context(), "users/"+userID, fetchUser(userID))
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return errorStatusCode(err), err
 	}
 
 	if err := encodeJSON(w, user); err != nil {
@@ -110,30 +355,183 @@ func usersHandler(w http.ResponseWriter, r *http.Request, userID string) (int, e
 	return http.StatusOK, nil
 }
 
+func starsHandler(w http.ResponseWriter, r *http.Request, userID string) (int, error) {
+	stars, err := results.get(r.Context(), path.Join("users", userID, "stars"), fetchStars(userID))
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	if err := encodeJSON(w, stars); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func connectionsHandler(w http.ResponseWriter, r *http.Request, userID, list string) (int, error) {
+	conns, err := results.get(r.Context(), path.Join("users", userID, list), fetchConnections(userID, list))
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	if err := encodeJSON(w, conns); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
 func talksHandler(w http.ResponseWriter, r *http.Request, talkStr string) (int, error) {
 	parts := strings.Split(talkStr, "/")
-	if len(parts) > 2 {
-		return http.StatusBadRequest, fmt.Errorf("invalid talk name, argument should be of form {user} or {user}/{talk}")
+	if len(parts) > 3 || (len(parts) == 3 && (len(parts[1]) == 0 || parts[2] != "slides")) {
+		return http.StatusBadRequest, fmt.Errorf("invalid talk name, argument should be of form {user}, {user}/{talk} or {user}/{talk}/slides")
 	}
 	userID := parts[0]
 	talkID := ""
-	if len(parts) == 2 {
+	if len(parts) >= 2 {
 		talkID = parts[1]
 	}
 
-	var opts *scraper.ScrapeOptions
-	if locationExt != nil {
-		opts = &scraper.ScrapeOptions{
-			Extensions: []scraper.Extension{locationExt},
-		}
+	// The slides are only scraped when asked for, as they make up most of the talk
+	slides := len(parts) == 3 || r.URL.Query().Get("slides") == "true"
+	key := path.Join("talks", userID, talkID)
+	if slides {
+		key += "+slides"
+	}
+	talks, err := results.get(r.Context(), key, fetchTalks(userID, talkID, slides))
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	var data interface{} = talks
+	if len(parts) == 3 {
+		data = talks.(speakerdeck.Talks)[0].Slides
+	}
+	if err := encodeJSON(w, data); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func categoriesHandler(w http.ResponseWriter, r *http.Request, category string) (int, error) {
+	if strings.Contains(category, "/") {
+		return http.StatusBadRequest, fmt.Errorf("invalid category, can't contain /")
+	}
+	maxPages, err := maxPagesParam(r, speakerdeck.DefaultCategoryMaxPages)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// "Technology" and "technology" are the same category
+	key := path.Join("categories", speakerdeck.CategorySlug(category), strconv.Itoa(maxPages))
+	cat, err := results.get(r.Context(), key, fetchCategory(category, maxPages))
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	if err := encodeJSON(w, cat); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func searchHandler(w http.ResponseWriter, r *http.Request, _ string) (int, error) {
+	// Searches ignore case and extra whitespace, so the query is normalized once, and the normalized
+	// query is both searched for and used as cache key
+	query := strings.Join(strings.Fields(strings.ToLower(r.URL.Query().Get("q"))), " ")
+	if len(query) == 0 {
+		return http.StatusBadRequest, fmt.Errorf("the q parameter is mandatory")
+	}
+	maxPages, err := maxPagesParam(r, speakerdeck.DefaultSearchMaxPages)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if r.URL.Query().Get("stream") == "true" {
+		return streamSearch(w, r, query, maxPages)
 	}
 
-	talks, err := speakerdeck.ScrapeTalks(userID, talkID, opts)
+	key := "search/" + strconv.Itoa(maxPages) + "/" + query
+	found, err := results.get(r.Context(), key, fetchSearch(query, maxPages))
 	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	if err := encodeJSON(w, found); err != nil {
 		return http.StatusInternalServerError, err
 	}
+	return http.StatusOK, nil
+}
+
+// streamSearch writes the talks found as newline-delimited JSON, flushing each talk as soon as it is
+// found. Streamed searches are not cached. Once the first talk has been written, errors can't change
+// the status code anymore, so they are only logged.
+func streamSearch(w http.ResponseWriter, r *http.Request, query string, maxPages int) (int, error) {
+	opts := scrapeOptions()
+	opts.MaxPages = maxPages
+	written := false
+	err := client.StreamSearch(r.Context(), query, opts, func(t speakerdeck.AuthoredTalkPreview) error {
+		if !written {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			written = true
+		}
+		if err := json.NewEncoder(w).Encode(t); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	})
+	if err != nil && written {
+		log.Errorf("search for %q failed after streaming started: %v", query, err)
+		return http.StatusOK, nil
+	}
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+	return http.StatusOK, nil
+}
 
-	if err := encodeJSON(w, talks); err != nil {
+// maxPagesParam returns the value of the max-pages query parameter, or def if it's not set
+func maxPagesParam(r *http.Request, def int) (int, error) {
+	pagesStr := r.URL.Query().Get("max-pages")
+	if len(pagesStr) == 0 {
+		return def, nil
+	}
+	maxPages, err := strconv.Atoi(pagesStr)
+	if err != nil || maxPages < 1 {
+		return 0, fmt.Errorf("invalid max-pages %q, should be a positive number", pagesStr)
+	}
+	return maxPages, nil
+}
+
+func graphHandler(w http.ResponseWriter, r *http.Request, userID string) (int, error) {
+	if strings.Contains(userID, "/") {
+		return http.StatusBadRequest, fmt.Errorf("invalid user name, can't contain /")
+	}
+	depth := 1
+	if depthStr := r.URL.Query().Get("depth"); len(depthStr) > 0 {
+		var err error
+		if depth, err = strconv.Atoi(depthStr); err != nil || depth < 0 || depth > *maxDepth {
+			return http.StatusBadRequest, fmt.Errorf("invalid depth %q, should be a number between 0 and %d", depthStr, *maxDepth)
+		}
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "graphml" {
+		return http.StatusBadRequest, fmt.Errorf("invalid format %q, should be json or graphml", format)
+	}
+
+	g, err := results.get(r.Context(), path.Join("graph", userID, strconv.Itoa(depth)), fetchGraph(userID, depth))
+	if err != nil {
+		return errorStatusCode(err), err
+	}
+
+	if format == "graphml" {
+		w.Header().Set("Content-Type", "application/graphml+xml")
+		err = g.(*speakerdeck.Graph).WriteGraphML(w)
+	} else {
+		err = encodeJSON(w, g)
+	}
+	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 	return http.StatusOK, nil
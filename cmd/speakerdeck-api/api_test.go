@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+// newTestAPI points the API at a new fake Speakerdeck server, and returns the server and the API handler.
+// Nothing is cached, so that every request reaches the fake server.
+func newTestAPI(t *testing.T) (*speakerdecktest.Server, http.Handler) {
+	s := speakerdecktest.NewServer()
+	client = s.NewClient()
+	results = newResultCache(0, 0, 0)
+	t.Cleanup(func() {
+		s.Close()
+		client, results = nil, nil
+	})
+	return s, newServeMux()
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "not found",
+			err:  &scraper.HTTPError{StatusCode: http.StatusNotFound},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "rate limited",
+			err:  &scraper.HTTPError{StatusCode: http.StatusTooManyRequests},
+			want: http.StatusTooManyRequests,
+		},
+		{
+			name: "server error",
+			err:  &scraper.HTTPError{StatusCode: http.StatusServiceUnavailable},
+			want: http.StatusBadGateway,
+		},
+		{
+			name: "network error",
+			err:  &scraper.HTTPError{Err: errors.New("connection refused")},
+			want: http.StatusBadGateway,
+		},
+		{
+			name: "parse error",
+			err:  scraper.Errors{&scraper.HookError{Err: &scraper.ParseError{Text: "many"}}},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "selector drift",
+			err:  scraper.Errors{&scraper.NoMatchError{DOMPath: ".talks"}},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "timed out",
+			err:  fmt.Errorf("scraping failed: %w", context.DeadlineExceeded),
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "timed out while fetching",
+			err:  &scraper.HTTPError{Err: context.DeadlineExceeded},
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "other",
+			err:  errors.New("something went wrong"),
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatusCode(tt.err); got != tt.want {
+				t.Errorf("errorStatusCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		failures   []int
+		wantStatus int
+	}{
+		{
+			name:       "user",
+			path:       "/api/users/luxas",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown user",
+			path:       "/api/users/nobody",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "rate limited",
+			path:       "/api/users/luxas",
+			failures:   []int{http.StatusTooManyRequests},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "upstream unavailable",
+			path:       "/api/users/luxas",
+			failures:   []int{http.StatusInternalServerError},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "unknown talk",
+			path:       "/api/talks/luxas/nothing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid path",
+			path:       "/api/talks/luxas/weave-ignite/transcript",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api := newTestAPI(t)
+			s.FailNext("/luxas", tt.failures...)
+
+			w := httptest.NewRecorder()
+			api.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		queries     []string
+		stream      bool
+		wantStatus  int
+		wantType    string
+		wantTalks   int
+		wantQuery   string
+		wantFetches int
+	}{
+		{
+			name:        "cached once for all casings",
+			queries:     []string{"Kubernetes", "kubernetes", "  KUBERNETES "},
+			wantStatus:  http.StatusOK,
+			wantTalks:   2,
+			wantQuery:   "kubernetes",
+			wantFetches: 1,
+		},
+		{
+			name:        "streamed",
+			queries:     []string{"Kubernetes", "kubernetes"},
+			stream:      true,
+			wantStatus:  http.StatusOK,
+			wantType:    "application/x-ndjson",
+			wantTalks:   2,
+			wantFetches: 2,
+		},
+		{
+			name:       "empty query",
+			queries:    []string{" "},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api := newTestAPI(t)
+			results = newResultCache(time.Hour, 0, 0)
+
+			for _, query := range tt.queries {
+				q := url.Values{"q": {query}}
+				if tt.stream {
+					q.Set("stream", "true")
+				}
+				w := httptest.NewRecorder()
+				api.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?"+q.Encode(), nil))
+				if w.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+				}
+				if w.Code != http.StatusOK {
+					continue
+				}
+				if ct := w.Header().Get("Content-Type"); len(tt.wantType) > 0 && !strings.HasPrefix(ct, tt.wantType) {
+					t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+				}
+
+				talks := []speakerdeck.AuthoredTalkPreview{}
+				if tt.stream {
+					for _, line := range strings.Split(strings.TrimSpace(w.Body.String()), "\n") {
+						talk := speakerdeck.AuthoredTalkPreview{}
+						if err := json.Unmarshal([]byte(line), &talk); err != nil {
+							t.Fatalf("invalid line %q: %v", line, err)
+						}
+						talks = append(talks, talk)
+					}
+				} else {
+					found := &speakerdeck.SearchResults{}
+					if err := json.Unmarshal(w.Body.Bytes(), found); err != nil {
+						t.Fatal(err)
+					}
+					if found.Query != tt.wantQuery {
+						t.Errorf("query = %q, want %q", found.Query, tt.wantQuery)
+					}
+					talks = found.TalkPreviews
+				}
+				if len(talks) != tt.wantTalks {
+					t.Errorf("got %d talks, want %d", len(talks), tt.wantTalks)
+				}
+			}
+			// All the results fit on the first page of the fake server
+			if got := s.Requests("/search"); got != tt.wantFetches {
+				t.Errorf("searched %d times, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
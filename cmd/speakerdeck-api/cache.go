@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fetchFunc scrapes the value for a cache key
+type fetchFunc func(ctx context.Context) (interface{}, error)
+
+// newResultCache creates a new resultCache. Entries are fresh for ttl, after which they are served
+// stale for up to staleTTL while being refreshed in the background, and then evicted. At most maxEntries
+// entries are kept; when full, the oldest entry is evicted. If maxEntries is zero or less, the amount of
+// entries isn't limited. If ttl is zero, nothing is cached, but concurrent identical requests are still
+// deduplicated.
+func newResultCache(ttl, staleTTL time.Duration, maxEntries int) *resultCache {
+	return &resultCache{
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		maxEntries: maxEntries,
+		entries:    map[string]*cacheEntry{},
+		calls:      map[string]*call{},
+	}
+}
+
+// resultCache caches scraped User and Talks objects by key, e.g. "talks/luxas", with
+// stale-while-revalidate semantics. Concurrent requests for the same key that need scraping
+// are deduplicated, so that only one scrape is in flight per key.
+type resultCache struct {
+	ttl        time.Duration
+	staleTTL   time.Duration
+	maxEntries int
+
+	mux     sync.Mutex
+	entries map[string]*cacheEntry
+	calls   map[string]*call
+}
+
+type cacheEntry struct {
+	value   interface{}
+	fetched time.Time
+}
+
+// call is an in-flight scrape of a key. It's cancelled when all the requests waiting for it are gone,
+// after which requests for the key start a new call.
+type call struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// get returns the value for key, from the cache if possible, or by calling fetch
+func (c *resultCache) get(ctx context.Context, key string, fetch fetchFunc) (interface{}, error) {
+	c.mux.Lock()
+	if e, ok := c.entries[key]; ok {
+		age := time.Since(e.fetched)
+		if age < c.ttl {
+			c.mux.Unlock()
+			return e.value, nil
+		}
+		if age < c.ttl+c.staleTTL {
+			// Serve the stale value, but refresh it in the background
+			c.startCall(key, fetch, false)
+			c.mux.Unlock()
+			log.Debugf("Serving stale %q while refreshing it", key)
+			return e.value, nil
+		}
+		delete(c.entries, key)
+	}
+	cl := c.startCall(key, fetch, true)
+	c.mux.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		c.mux.Lock()
+		cl.waiters--
+		if cl.waiters == 0 {
+			cl.cancel()
+			// Requests arriving while the cancelled call winds down must not join it
+			if c.calls[key] == cl {
+				delete(c.calls, key)
+			}
+		}
+		c.mux.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// refresh scrapes key in the background, unless it's already being scraped
+func (c *resultCache) refresh(key string, fetch fetchFunc) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.startCall(key, fetch, false)
+}
+
+// startCall returns the in-flight call for key, or starts a new one. If wait is true, the caller
+// is registered as a waiter of the call. c.mux must be held.
+func (c *resultCache) startCall(key string, fetch fetchFunc, wait bool) *call {
+	cl, ok := c.calls[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		cl = &call{done: make(chan struct{}), cancel: cancel}
+		// Background refreshes count as a waiter themselves, so they're never cancelled
+		if !wait {
+			cl.waiters++
+		}
+		c.calls[key] = cl
+
+		go func() {
+			defer cancel()
+			cl.value, cl.err = fetch(ctx)
+
+			c.mux.Lock()
+			if c.calls[key] == cl {
+				delete(c.calls, key)
+			}
+			if cl.err == nil && c.ttl > 0 {
+				c.add(key, cl.value)
+			}
+			c.mux.Unlock()
+
+			if cl.err != nil && ctx.Err() == nil {
+				log.Warnf("Could not refresh %q: %v", key, cl.err)
+			}
+			close(cl.done)
+		}()
+	}
+	if wait {
+		cl.waiters++
+	}
+	return cl
+}
+
+// add caches value for key, evicting expired entries, and the oldest entry if the cache is full.
+// c.mux must be held.
+func (c *resultCache) add(key string, value interface{}) {
+	now := time.Now()
+	oldestKey, oldest := "", now
+	for k, e := range c.entries {
+		if now.Sub(e.fetched) >= c.ttl+c.staleTTL {
+			delete(c.entries, k)
+		} else if k != key && e.fetched.Before(oldest) {
+			oldestKey, oldest = k, e.fetched
+		}
+	}
+	c.entries[key] = &cacheEntry{value: value, fetched: now}
+	if c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// watch refreshes the given keys every interval, until ctx is done
+func (c *resultCache) watch(ctx context.Context, interval time.Duration, fetches map[string]fetchFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for key, fetch := range fetches {
+			c.refresh(key, fetch)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
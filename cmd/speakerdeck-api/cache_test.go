@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		staleTTL time.Duration
+		// wait is how long to wait between the two requests
+		wait       time.Duration
+		wantSecond int64
+		wantFetch  int64
+	}{
+		{
+			name:       "fresh",
+			ttl:        time.Hour,
+			wantSecond: 1,
+			wantFetch:  1,
+		},
+		{
+			name:       "stale while revalidating",
+			ttl:        time.Millisecond,
+			staleTTL:   time.Hour,
+			wait:       5 * time.Millisecond,
+			wantSecond: 1,
+			wantFetch:  2,
+		},
+		{
+			name:       "expired",
+			ttl:        time.Millisecond,
+			staleTTL:   time.Millisecond,
+			wait:       5 * time.Millisecond,
+			wantSecond: 2,
+			wantFetch:  2,
+		},
+		{
+			name:       "not cached",
+			wantSecond: 2,
+			wantFetch:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newResultCache(tt.ttl, tt.staleTTL, 0)
+			var fetches int64
+			fetched := make(chan struct{}, 2)
+			fetch := func(ctx context.Context) (interface{}, error) {
+				defer func() { fetched <- struct{}{} }()
+				return atomic.AddInt64(&fetches, 1), nil
+			}
+
+			if v, err := c.get(context.Background(), "key", fetch); err != nil || v != int64(1) {
+				t.Fatalf("first get() = %v, %v, want 1", v, err)
+			}
+			<-fetched
+			time.Sleep(tt.wait)
+			if v, err := c.get(context.Background(), "key", fetch); err != nil || v != tt.wantSecond {
+				t.Fatalf("second get() = %v, %v, want %d", v, err, tt.wantSecond)
+			}
+			if tt.wantFetch > 1 {
+				// Wait for the possible background refresh
+				<-fetched
+			}
+			if got := atomic.LoadInt64(&fetches); got != tt.wantFetch {
+				t.Errorf("fetched %d times, want %d", got, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestResultCacheDeduplication(t *testing.T) {
+	c := newResultCache(time.Hour, 0, 0)
+	var fetches int64
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt64(&fetches, 1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.get(context.Background(), "key", fetch); err != nil || v != "value" {
+				t.Errorf("get() = %v, %v, want %q", v, err, "value")
+			}
+		}()
+	}
+	// Let the requests pile up on the in-flight call
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if fetches != 1 {
+		t.Errorf("fetched %d times, want 1", fetches)
+	}
+}
+
+func TestResultCacheCancel(t *testing.T) {
+	c := newResultCache(time.Hour, 0, 0)
+	cancelled := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := c.get(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("get() error = %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the fetch wasn't cancelled when its only waiter left")
+	}
+
+	// New requests must not join the cancelled call
+	v, err := c.get(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return "value", nil
+	})
+	if err != nil || v != "value" {
+		t.Errorf("get() after cancelling = %v, %v, want %q", v, err, "value")
+	}
+}
+
+func TestResultCacheMaxEntries(t *testing.T) {
+	c := newResultCache(time.Hour, 0, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		key := key
+		if _, err := c.get(context.Background(), key, func(ctx context.Context) (interface{}, error) {
+			return key, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// Make sure the entries are fetched at different times
+		time.Sleep(time.Millisecond)
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for key, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		if _, ok := c.entries[key]; ok != want {
+			t.Errorf("entry %q cached = %v, want %v", key, ok, want)
+		}
+	}
+}
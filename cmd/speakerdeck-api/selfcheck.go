@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+	log "github.com/sirupsen/logrus"
+)
+
+// runSelfcheck runs the scrapers against offline fixture pages (or the live site, if -live is given),
+// and prints a report of how the hooks matched each page. This is useful for detecting when the
+// Speakerdeck markup has drifted from what the scrapers expect. The report is written to stdout, errors
+// to stderr, and the exit code is returned.
+func runSelfcheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("selfcheck", flag.ExitOnError)
+	live := fs.Bool("live", false, "Check the pages at -base-url instead of offline fixture pages")
+	user := fs.String("user", "luxas", "What user to check the pages of")
+	talk := fs.String("talk", "", "What talk to check the page of. Defaults to the first talk of the user")
+	category := fs.String("category", "", "What category to check the first page of. Defaults to the category of the talk")
+	query := fs.String("query", "kubernetes", "What search query to check the first page of results of")
+	fs.Parse(args)
+
+	c := client
+	if !*live {
+		s := speakerdecktest.NewServer()
+		defer s.Close()
+		c = s.NewClient()
+	}
+
+	mux := &sync.Mutex{}
+	reports := []*scraper.PageReport{}
+	logLevel := log.WarnLevel
+	opts := scrapeOptions()
+	opts.LogLevel = &logLevel
+	opts.OnPage = func(r *scraper.PageReport) {
+		mux.Lock()
+		reports = append(reports, r)
+		mux.Unlock()
+	}
+
+	failed := false
+	ctx := context.Background()
+	u, err := c.ScrapeUserContext(ctx, *user, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		failed = true
+	}
+
+	if _, err := c.ScrapeStarsContext(ctx, *user, opts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		failed = true
+	}
+	if _, err := c.ScrapeFollowersContext(ctx, *user, opts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		failed = true
+	}
+
+	talkID := *talk
+	if len(talkID) == 0 && u != nil && len(u.TalkPreviews) > 0 {
+		talkID = u.TalkPreviews[0].ID
+	}
+	categoryName := *category
+	if len(talkID) > 0 {
+		talkOpts := *opts
+		talkOpts.Extensions = append(talkOpts.Extensions, &speakerdeck.SlidesExtension{})
+		talks, err := c.ScrapeTalksContext(ctx, *user, talkID, &talkOpts)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			failed = true
+		}
+		if len(categoryName) == 0 && len(talks) > 0 {
+			categoryName = talks[0].Category
+		}
+	}
+	if len(categoryName) > 0 {
+		categoryOpts := *opts
+		categoryOpts.MaxPages = 1
+		if _, err := c.ScrapeCategoryContext(ctx, categoryName, &categoryOpts); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			failed = true
+		}
+	}
+	if len(*query) > 0 {
+		searchOpts := *opts
+		searchOpts.MaxPages = 1
+		if _, err := c.ScrapeSearchContext(ctx, *query, &searchOpts); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			failed = true
+		}
+	}
+
+	if drifted := printDriftReport(stdout, reports); drifted > 0 {
+		failed = true
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// printDriftReport prints how the hooks matched each page, and returns how many hooks drifted
+func printDriftReport(w io.Writer, reports []*scraper.PageReport) int {
+	drifted, total := 0, 0
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%s\n", r.Scraper, r.URL)
+		fmt.Fprintf(tw, "  STATUS\tDOMPATH\tMATCHES\tEXPECTED\n")
+		for _, h := range r.Hooks {
+			status := "ok"
+			if !h.OK() {
+				status = "DRIFTED"
+				drifted++
+			}
+			total++
+			fmt.Fprintf(tw, "  %s\t%s\t%d\t%s\n", status, h.DOMPath, h.Matches, h.Expected())
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d of %d hooks drifted on %d pages\n", drifted, total, len(reports))
+	return drifted
+}
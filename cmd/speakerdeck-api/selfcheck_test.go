@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api/scraper"
+)
+
+func TestSelfcheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantCode     int
+		wantContains []string
+	}{
+		{
+			name:         "fixtures",
+			wantCode:     0,
+			wantContains: []string{"UserScraper", "TalkScraper", "CategoryScraper", "SearchScraper", "\n0 of "},
+		},
+		{
+			name:         "user without talks",
+			args:         []string{"-user", "kubefan", "-query", ""},
+			wantCode:     0,
+			wantContains: []string{"UserScraper", "\n0 of "},
+		},
+		{
+			name:         "unknown user",
+			args:         []string{"-user", "nobody", "-query", ""},
+			wantCode:     1,
+			wantContains: []string{"on 0 pages"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if code := runSelfcheck(tt.args, stdout, stderr); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d: %s", code, tt.wantCode, stderr)
+			}
+			for _, s := range tt.wantContains {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("the report doesn't contain %q:\n%s", s, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintDriftReport(t *testing.T) {
+	reports := []*scraper.PageReport{
+		{
+			Scraper: "TalkScraper",
+			URL:     "https://speakerdeck.com/luxas/weave-ignite",
+			Hooks: []scraper.HookReport{
+				{DOMPath: ".deck-title", Required: true, MinMatches: 1, MaxMatches: 1, Matches: 1},
+				{DOMPath: ".deck-date", Required: true, MinMatches: 1, Matches: 0},
+				{DOMPath: ".deck-description", Matches: 0},
+			},
+		},
+		{
+			Scraper: "UserScraper",
+			URL:     "https://speakerdeck.com/luxas",
+			Hooks: []scraper.HookReport{
+				{DOMPath: ".talks li", MaxMatches: 2, Matches: 3},
+			},
+		},
+	}
+	w := &bytes.Buffer{}
+	if drifted := printDriftReport(w, reports); drifted != 2 {
+		t.Errorf("printDriftReport() = %d, want 2", drifted)
+	}
+
+	lines := strings.Split(w.String(), "\n")
+	for _, want := range []struct{ status, domPath, expected string }{
+		{"ok", ".deck-title", "1"},
+		{"DRIFTED", ".deck-date", "1..*"},
+		{"ok", ".deck-description", "0..*"},
+		{"DRIFTED", ".talks li", "0..2"},
+	} {
+		found := false
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && fields[0] == want.status && strings.Contains(line, want.domPath) && fields[len(fields)-1] == want.expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no %s line for %q expecting %s in:\n%s", want.status, want.domPath, want.expected, w)
+		}
+	}
+	if !strings.Contains(w.String(), "2 of 4 hooks drifted on 2 pages") {
+		t.Errorf("unexpected summary in:\n%s", w)
+	}
+}
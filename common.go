@@ -2,22 +2,13 @@ package speakerdeck
 
 import (
 	"fmt"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-const (
-	speakerdeckRootURL = "https://speakerdeck.com"
-	httpsPrefix        = "https:"
-)
-
-var linkRegexp = regexp.MustCompile(`http[s]?://[a-zA-Z-_/0-9\.#=&]*`)
-
-func sdPrefix(s string) string {
-	return fmt.Sprintf("%s%s", speakerdeckRootURL, s)
-}
+const httpsPrefix = "https:"
 
 func parseDate(dateStr string) (time.Time, error) {
 	// sanitize the text
@@ -50,3 +41,30 @@ func parseNumber(numstr string) (uint32, error) {
 	}
 	return uint32(multiplier * n), nil
 }
+
+// parseSrcset parses the srcset attribute of an img element, e.g. "a.jpg 1024w, b.jpg 2048w", into
+// images ordered by increasing width. Candidates described by pixel density instead of width are skipped
+func parseSrcset(srcset string, absoluteURL func(string) string) ([]SlideImage, error) {
+	images := []SlideImage{}
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) != 2 {
+			if len(fields) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid srcset candidate %q", strings.TrimSpace(candidate))
+		}
+		if !strings.HasSuffix(fields[1], "w") {
+			continue
+		}
+		width, err := strconv.Atoi(strings.TrimSuffix(fields[1], "w"))
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, SlideImage{Width: width, Link: absoluteURL(fields[0])})
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Width < images[j].Width
+	})
+	return images, nil
+}
@@ -0,0 +1,47 @@
+package speakerdeck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint32
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: " 42 ", want: 42},
+		{in: "1,234", want: 1234},
+		{in: "1.5k", want: 1500},
+		{in: "many", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNumber(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNumber(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNumber(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2019, time.November, 20, 0, 0, 0, 0, time.UTC)
+	for _, in := range []string{"November 20, 2019", "\n  Nov 20, 2019 \n"} {
+		got, err := parseDate(in)
+		if err != nil {
+			t.Errorf("parseDate(%q) error = %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseDate(%q) = %v, want %v", in, got, want)
+		}
+	}
+	if _, err := parseDate("yesterday"); err == nil {
+		t.Errorf("parseDate(%q) succeeded, want an error", "yesterday")
+	}
+}
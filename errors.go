@@ -0,0 +1,133 @@
+package speakerdeck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/luxas/speakerdeck-api/scraper"
+)
+
+var (
+	// ErrTooManyFailures is returned when a scrape is aborted because too many talks failed, according
+	// to the MaxFailures or FailFast options
+	ErrTooManyFailures = errors.New("too many talks failed to be scraped")
+	// ErrUserNotFound matches errors for users that don't exist, using errors.Is
+	ErrUserNotFound = errors.New("user not found")
+	// ErrTalkNotFound matches errors for talks that don't exist, using errors.Is
+	ErrTalkNotFound = errors.New("talk not found")
+	// ErrCategoryNotFound matches errors for categories that don't exist, using errors.Is
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+// UserError describes why a user could not be scraped
+type UserError struct {
+	// Handle is the handle of the failed user
+	Handle string
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements error
+func (e *UserError) Error() string {
+	return fmt.Sprintf("could not scrape user %q: %v", e.Handle, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the error match ErrUserNotFound if the user page doesn't exist
+func (e *UserError) Is(target error) bool {
+	return target == ErrUserNotFound && errors.Is(e.Err, scraper.ErrNotFound)
+}
+
+// CategoryError describes why a category could not be scraped
+type CategoryError struct {
+	// Category is the URL-encoded name of the failed category
+	Category string
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements error
+func (e *CategoryError) Error() string {
+	return fmt.Sprintf("could not scrape category %q: %v", e.Category, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *CategoryError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the error match ErrCategoryNotFound if the category page doesn't exist
+func (e *CategoryError) Is(target error) bool {
+	return target == ErrCategoryNotFound && errors.Is(e.Err, scraper.ErrNotFound)
+}
+
+// TalkError describes why a specific talk could not be scraped. It's encoded to JSON with the message
+// of Err as the "error" field.
+type TalkError struct {
+	// TalkID is the ID of the failed talk
+	TalkID string `json:"talkID"`
+	// URL is the URL of the failed talk
+	URL string `json:"url"`
+	// StatusCode is the HTTP status code of the talk page, if fetching it failed
+	StatusCode int `json:"statusCode,omitempty"`
+	// DOMPath is the DOMPath of the hook that failed, if any
+	DOMPath string `json:"domPath,omitempty"`
+	// Err is the underlying error
+	Err error `json:"-"`
+}
+
+func newTalkError(talkID, talkURL string, err error) *TalkError {
+	talkErr := &TalkError{
+		TalkID: talkID,
+		URL:    talkURL,
+		Err:    err,
+	}
+
+	var httpErr *scraper.HTTPError
+	if errors.As(err, &httpErr) {
+		talkErr.StatusCode = httpErr.StatusCode
+	}
+	var hookErr *scraper.HookError
+	var noMatchErr *scraper.NoMatchError
+	if errors.As(err, &hookErr) {
+		talkErr.DOMPath = hookErr.DOMPath
+	} else if errors.As(err, &noMatchErr) {
+		talkErr.DOMPath = noMatchErr.DOMPath
+	}
+	return talkErr
+}
+
+// Error implements error
+func (e *TalkError) Error() string {
+	return fmt.Sprintf("could not scrape talk %q: %v", e.TalkID, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TalkError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the error match ErrTalkNotFound if the talk page doesn't exist
+func (e *TalkError) Is(target error) bool {
+	return target == ErrTalkNotFound && errors.Is(e.Err, scraper.ErrNotFound)
+}
+
+// MarshalJSON implements json.Marshaler. Errors generally don't encode to JSON, so Err is
+// encoded as its message.
+func (e *TalkError) MarshalJSON() ([]byte, error) {
+	// talkError has the fields, but not the methods, of TalkError
+	type talkError TalkError
+	msg := ""
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+	return json.Marshal(&struct {
+		*talkError
+		Error string `json:"error"`
+	}{(*talkError)(e), msg})
+}
@@ -0,0 +1,67 @@
+package speakerdeck_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+func TestScrapeUserFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []int
+		retry    *scraper.RetryPolicy
+		wantErr  error
+	}{
+		{
+			name:     "server error",
+			failures: []int{http.StatusInternalServerError},
+			wantErr:  scraper.ErrUpstreamUnavailable,
+		},
+		{
+			name:     "rate limited",
+			failures: []int{http.StatusTooManyRequests},
+			wantErr:  scraper.ErrRateLimited,
+		},
+		{
+			name:     "retried server errors",
+			failures: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			retry:    &scraper.RetryPolicy{MaxAttempts: 3, InitialBackoff: 1},
+		},
+		{
+			name:     "too many server errors",
+			failures: []int{http.StatusBadGateway, http.StatusBadGateway},
+			retry:    &scraper.RetryPolicy{MaxAttempts: 2, InitialBackoff: 1},
+			wantErr:  scraper.ErrUpstreamUnavailable,
+		},
+		{
+			name:     "not found is not retried",
+			failures: []int{http.StatusNotFound},
+			retry:    &scraper.RetryPolicy{MaxAttempts: 3, InitialBackoff: 1},
+			wantErr:  speakerdeck.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			s.FailNext("/janedoe", tt.failures...)
+
+			_, err := s.NewClient().ScrapeUser("janedoe", &scraper.ScrapeOptions{Retry: tt.retry})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ScrapeUser() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScrapeUser() error = %v, want %v", err, tt.wantErr)
+			}
+			var userErr *speakerdeck.UserError
+			if err != nil && !errors.As(err, &userErr) {
+				t.Errorf("ScrapeUser() error is a %T, want a *UserError", err)
+			}
+		})
+	}
+}
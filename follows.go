@@ -0,0 +1,144 @@
+package speakerdeck
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
+)
+
+// ScrapeFollowers returns the users following the given user.
+// ScrapeFollowers uses DefaultClient, see Client.ScrapeFollowers.
+func ScrapeFollowers(userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return DefaultClient.ScrapeFollowers(userHandle, opts)
+}
+
+// ScrapeFollowersContext works like ScrapeFollowers, but stops scraping when ctx is done.
+// ScrapeFollowersContext uses DefaultClient, see Client.ScrapeFollowersContext.
+func ScrapeFollowersContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return DefaultClient.ScrapeFollowersContext(ctx, userHandle, opts)
+}
+
+// ScrapeFollowing returns the users the given user is following.
+// ScrapeFollowing uses DefaultClient, see Client.ScrapeFollowing.
+func ScrapeFollowing(userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return DefaultClient.ScrapeFollowing(userHandle, opts)
+}
+
+// ScrapeFollowingContext works like ScrapeFollowing, but stops scraping when ctx is done.
+// ScrapeFollowingContext uses DefaultClient, see Client.ScrapeFollowingContext.
+func ScrapeFollowingContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return DefaultClient.ScrapeFollowingContext(ctx, userHandle, opts)
+}
+
+// ScrapeFollowers returns the users following the given user
+func (c *Client) ScrapeFollowers(userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return c.ScrapeFollowersContext(context.Background(), userHandle, opts)
+}
+
+// ScrapeFollowersContext works like ScrapeFollowers, but stops scraping when ctx is done. No further
+// pages of followers are visited after ctx is cancelled.
+func (c *Client) ScrapeFollowersContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return c.scrapeConnections(ctx, userHandle, "followers", opts)
+}
+
+// ScrapeFollowing returns the users the given user is following
+func (c *Client) ScrapeFollowing(userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return c.ScrapeFollowingContext(context.Background(), userHandle, opts)
+}
+
+// ScrapeFollowingContext works like ScrapeFollowing, but stops scraping when ctx is done. No further
+// pages of followed users are visited after ctx is cancelled.
+func (c *Client) ScrapeFollowingContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	return c.scrapeConnections(ctx, userHandle, "following", opts)
+}
+
+// scrapeConnections scrapes the given list of users, i.e. "followers" or "following", of the user
+func (c *Client) scrapeConnections(ctx context.Context, userHandle, list string, opts *scraper.ScrapeOptions) (*Connections, error) {
+	if len(userHandle) == 0 {
+		return nil, fmt.Errorf("userHandle is mandatory!")
+	}
+
+	listURL := c.url(userHandle, list)
+	conns, err := typed.Scrape[Connections](ctx, listURL, (&ConnectionsScraper{}).Typed(), c.scrapeOptions(opts))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &UserError{Handle: userHandle, Err: err}
+	}
+	conns.Author.Link = c.url(userHandle)
+	conns.Link = listURL
+	return conns, nil
+}
+
+var _ scraper.Scraper = &ConnectionsScraper{}
+
+// ConnectionsScraper implements scraper.Scraper, and scrapes both the followers and following
+// lists of users, as they share the same layout. Its typed.Scraper[Connections] is returned by Typed
+type ConnectionsScraper struct{}
+
+// Name returns the name of the ConnectionsScraper
+func (s *ConnectionsScraper) Name() string {
+	return "ConnectionsScraper"
+}
+
+// Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
+// of them
+func (s *ConnectionsScraper) Hooks() []scraper.Hook {
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *ConnectionsScraper) InitialData() interface{} {
+	return NewConnections()
+}
+
+// Typed returns the ConnectionsScraper as a typed.Scraper[Connections], whose handlers get the *Connections
+// passed directly
+func (s *ConnectionsScraper) Typed() typed.Scraper[Connections] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewConnections)
+}
+
+func (s *ConnectionsScraper) typedHooks() []typed.Hook[Connections] {
+	return []typed.Hook[Connections]{
+		{
+			DOMPath:    ".sd-main > :first-child .row",
+			Handler:    onConnectionsAuthor,
+			Required:   true,
+			MaxMatches: 1,
+		},
+		{
+			DOMPath: ".container a.user-card[href]",
+			Handler: onConnectionFound,
+		},
+		{
+			DOMPath:    ".next .page-link[rel='next']",
+			Handler:    onNextPage[Connections],
+			MaxMatches: 1,
+		},
+	}
+}
+
+func onConnectionsAuthor(e *colly.HTMLElement, c *Connections) (*string, error) {
+	c.Author.Name = e.ChildText("h1.m-0")
+	c.Author.Handle = e.ChildText("div.text-muted")
+	c.Author.AvatarLink = httpsPrefix + e.ChildAttr("img", "src")
+	return nil, nil
+}
+
+func onConnectionFound(e *colly.HTMLElement, c *Connections) (*string, error) {
+	a := Author{
+		Link:       e.Request.AbsoluteURL(e.Attr("href")),
+		Name:       strings.TrimSpace(e.ChildText(".user-card-name")),
+		AvatarLink: httpsPrefix + e.ChildAttr("img", "src"),
+	}
+	a.Handle = path.Base(a.Link)
+	c.Authors = append(c.Authors, a)
+	return nil, nil
+}
@@ -0,0 +1,291 @@
+package speakerdeck
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/luxas/speakerdeck-api/scraper"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultGraphMaxPages is how many pages of each followers and following list are scraped by
+// ScrapeGraph, unless opts.MaxPages is set. This bounds the amount of users added per crawled user
+const DefaultGraphMaxPages = 3
+
+// Graph is a social graph of Speakerdeck users, connected by who follows who
+type Graph struct {
+	// Seed is the handle of the user the graph was crawled from
+	Seed string `json:"seed"`
+
+	// Depth is the maximum distance from the seed user that was crawled
+	Depth int `json:"depth"`
+
+	// Nodes contains the users in the graph, ordered by their distance from the seed user, and handle
+	Nodes []GraphNode `json:"nodes"`
+
+	// Edges contains the follow relationships between the users, ordered by From and To
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode describes a user in the Graph
+type GraphNode struct {
+	// Author describes the Speakerdeck profile of the user
+	Author
+
+	// Depth is the distance of the user from the seed user in the graph
+	Depth int `json:"depth"`
+}
+
+// GraphEdge describes that the From user follows the To user
+type GraphEdge struct {
+	// From is the handle of the following user
+	From string `json:"from"`
+
+	// To is the handle of the followed user
+	To string `json:"to"`
+}
+
+// ScrapeGraph crawls the followers and following lists of users, starting from seedHandle.
+// ScrapeGraph uses DefaultClient, see Client.ScrapeGraph.
+func ScrapeGraph(seedHandle string, depth int, opts *scraper.ScrapeOptions) (*Graph, error) {
+	return DefaultClient.ScrapeGraph(seedHandle, depth, opts)
+}
+
+// ScrapeGraphContext works like ScrapeGraph, but stops scraping when ctx is done.
+// ScrapeGraphContext uses DefaultClient, see Client.ScrapeGraphContext.
+func ScrapeGraphContext(ctx context.Context, seedHandle string, depth int, opts *scraper.ScrapeOptions) (*Graph, error) {
+	return DefaultClient.ScrapeGraphContext(ctx, seedHandle, depth, opts)
+}
+
+// ScrapeGraph crawls the followers and following lists of users, starting from seedHandle, and
+// returns the social graph of all users found at most depth follow relationships away from the
+// seed user. The lists of users at depth are not crawled, but the users themselves are included,
+// so follow relationships between two users at depth are not known. Every user is only crawled
+// once. At most opts.MaxPages pages of each list are scraped, or DefaultGraphMaxPages if not set.
+func (c *Client) ScrapeGraph(seedHandle string, depth int, opts *scraper.ScrapeOptions) (*Graph, error) {
+	return c.ScrapeGraphContext(context.Background(), seedHandle, depth, opts)
+}
+
+// ScrapeGraphContext works like ScrapeGraph, but stops scraping when ctx is done. Failing to crawl
+// the seed user returns an error, while users further away that fail to be crawled are logged and
+// left out of the crawl.
+func (c *Client) ScrapeGraphContext(ctx context.Context, seedHandle string, depth int, opts *scraper.ScrapeOptions) (*Graph, error) {
+	if len(seedHandle) == 0 {
+		return nil, fmt.Errorf("seedHandle is mandatory!")
+	}
+	if depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative, got %d", depth)
+	}
+
+	opts = c.scrapeOptions(opts)
+	if opts.MaxPages == 0 {
+		opts.MaxPages = DefaultGraphMaxPages
+	}
+
+	mux := &sync.Mutex{}
+	nodes := map[string]*GraphNode{
+		seedHandle: {Author: Author{Handle: seedHandle, Link: c.url(seedHandle)}},
+	}
+	edges := map[GraphEdge]bool{}
+
+	// Crawl the graph breadth-first, one level of depth at a time
+	frontier := []string{seedHandle}
+	for d := 0; d < depth && len(frontier) > 0; d++ {
+		next := []string{}
+		var seedErr error
+
+		handles := make(chan string)
+		wg := &sync.WaitGroup{}
+		for i := 0; i < opts.RateLimit.Concurrency() && i < len(frontier); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for handle := range handles {
+					followers, following, err := c.scrapeFollows(ctx, handle, opts)
+
+					mux.Lock()
+					switch {
+					case err == nil:
+						// Profile data from the user's own page is more complete than from the lists.
+						// The handle is kept as crawled though, as that is what the edges refer to
+						node := nodes[handle]
+						node.Name = followers.Author.Name
+						node.AvatarLink = followers.Author.AvatarLink
+						node.Link = followers.Author.Link
+						// add registers the user if not seen before, and queues it for the next level
+						add := func(a Author) {
+							if _, ok := nodes[a.Handle]; !ok {
+								nodes[a.Handle] = &GraphNode{Author: a, Depth: d + 1}
+								next = append(next, a.Handle)
+							}
+						}
+						for _, a := range followers.Authors {
+							add(a)
+							edges[GraphEdge{From: a.Handle, To: handle}] = true
+						}
+						for _, a := range following.Authors {
+							add(a)
+							edges[GraphEdge{From: handle, To: a.Handle}] = true
+						}
+					case ctx.Err() != nil:
+					case handle == seedHandle:
+						seedErr = err
+					default:
+						log.Warnf("could not crawl the connections of user %q: %v", handle, err)
+					}
+					mux.Unlock()
+				}
+			}()
+		}
+
+	feed:
+		for _, handle := range frontier {
+			select {
+			case handles <- handle:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(handles)
+		wg.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if seedErr != nil {
+			return nil, seedErr
+		}
+		frontier = next
+	}
+
+	g := &Graph{
+		Seed:  seedHandle,
+		Depth: depth,
+		Nodes: make([]GraphNode, 0, len(nodes)),
+		Edges: make([]GraphEdge, 0, len(edges)),
+	}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	for e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Depth != g.Nodes[j].Depth {
+			return g.Nodes[i].Depth < g.Nodes[j].Depth
+		}
+		return g.Nodes[i].Handle < g.Nodes[j].Handle
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g, nil
+}
+
+// scrapeFollows scrapes both the followers and following lists of the user
+func (c *Client) scrapeFollows(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (followers, following *Connections, err error) {
+	if followers, err = c.ScrapeFollowersContext(ctx, userHandle, opts); err != nil {
+		return nil, nil, err
+	}
+	if following, err = c.ScrapeFollowingContext(ctx, userHandle, opts); err != nil {
+		return nil, nil, err
+	}
+	return followers, following, nil
+}
+
+// WriteJSON writes the graph to w as indented JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(g)
+}
+
+// graphML is the root element of a GraphML document, see http://graphml.graphdrawing.org
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph to w as a directed GraphML document, which can be imported into
+// e.g. Gephi or yEd. The nodes are identified by the user handles, and have the name, link,
+// avatarLink and depth attributes.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := &graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "link", For: "node", AttrName: "link", AttrType: "string"},
+			{ID: "avatarLink", For: "node", AttrName: "avatarLink", AttrType: "string"},
+			{ID: "depth", For: "node", AttrName: "depth", AttrType: "int"},
+		},
+		Graph: graphMLGraph{
+			ID:          g.Seed,
+			EdgeDefault: "directed",
+		},
+	}
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.Handle,
+			Data: []graphMLData{
+				{Key: "name", Value: n.Name},
+				{Key: "link", Value: n.Link},
+				{Key: "avatarLink", Value: n.AvatarLink},
+				{Key: "depth", Value: fmt.Sprintf("%d", n.Depth)},
+			},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e.From, Target: e.To})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,277 @@
+package speakerdeck_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+func TestScrapeGraph(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	tests := []struct {
+		name  string
+		seed  string
+		depth int
+		// wantNodes lists the nodes as "{handle}:{depth}", in order
+		wantNodes []string
+		// wantEdges lists the edges as "{from}->{to}", in order
+		wantEdges []string
+		// crawled lists the users whose followers and following lists are expected to be
+		// scraped, exactly once each
+		crawled []string
+		wantErr error
+	}{
+		{
+			name:      "depth 0 only includes the seed",
+			seed:      "luxas",
+			depth:     0,
+			wantNodes: []string{"luxas:0"},
+			wantEdges: []string{},
+		},
+		{
+			name:      "depth 1",
+			seed:      "kubefan",
+			depth:     1,
+			wantNodes: []string{"kubefan:0", "janedoe:1", "luxas:1"},
+			wantEdges: []string{"kubefan->janedoe", "kubefan->luxas"},
+			crawled:   []string{"kubefan"},
+		},
+		{
+			name:      "depth 2 crawls every user once and deduplicates edges",
+			seed:      "kubefan",
+			depth:     2,
+			wantNodes: []string{"kubefan:0", "janedoe:1", "luxas:1"},
+			wantEdges: []string{"janedoe->luxas", "kubefan->janedoe", "kubefan->luxas", "luxas->janedoe"},
+			crawled:   []string{"kubefan", "janedoe", "luxas"},
+		},
+		{
+			name:      "the seed keeps the handle it was crawled as",
+			seed:      "Luxas",
+			depth:     1,
+			wantNodes: []string{"Luxas:0", "janedoe:1", "kubefan:1"},
+			wantEdges: []string{"Luxas->janedoe", "janedoe->Luxas", "kubefan->Luxas"},
+			crawled:   []string{"Luxas"},
+		},
+		{
+			name:    "unknown seed",
+			seed:    "nobody",
+			depth:   1,
+			wantErr: speakerdeck.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+
+			g, err := s.NewClient().ScrapeGraph(tt.seed, tt.depth, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScrapeGraph() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScrapeGraph() error = %v", err)
+			}
+
+			// Users are requested as crawled, which may differ in case from their own handle
+			requested := map[string]string{}
+			for _, u := range users {
+				requested[strings.ToLower(u.Handle)] = u.Handle
+			}
+			for _, h := range tt.crawled {
+				requested[strings.ToLower(h)] = h
+			}
+			for lower, h := range requested {
+				wantRequests := 0
+				for _, c := range tt.crawled {
+					if strings.ToLower(c) == lower {
+						wantRequests = 1
+					}
+				}
+				for _, list := range []string{"followers", "following"} {
+					if got := s.Requests("/" + h + "/" + list); got != wantRequests {
+						t.Errorf("fetched the %s of %q %d times, want %d", list, h, got, wantRequests)
+					}
+				}
+			}
+
+			if g.Seed != tt.seed || g.Depth != tt.depth {
+				t.Errorf("graph of %q at depth %d, want %q at depth %d", g.Seed, g.Depth, tt.seed, tt.depth)
+			}
+			nodes := []string{}
+			for _, n := range g.Nodes {
+				nodes = append(nodes, n.Handle+":"+strconv.Itoa(n.Depth))
+			}
+			if !reflect.DeepEqual(nodes, tt.wantNodes) {
+				t.Errorf("nodes = %q, want %q", nodes, tt.wantNodes)
+			}
+			edges := []string{}
+			for _, e := range g.Edges {
+				edges = append(edges, e.From+"->"+e.To)
+			}
+			if !reflect.DeepEqual(edges, tt.wantEdges) {
+				t.Errorf("edges = %q, want %q", edges, tt.wantEdges)
+			}
+
+			// Nodes are described by either their own profile, or the lists they were found in
+			for _, n := range g.Nodes {
+				if link := s.URL + "/" + n.Handle; n.Link != link {
+					t.Errorf("node %q has link %q, want %q", n.Handle, n.Link, link)
+				}
+				if tt.depth == 0 {
+					continue
+				}
+				for _, u := range users {
+					if !strings.EqualFold(u.Handle, n.Handle) {
+						continue
+					}
+					if n.Name != u.Name || n.AvatarLink != "https:"+u.AvatarLink {
+						t.Errorf("node %q is %q (%q), want %q (%q)", n.Handle, n.Name, n.AvatarLink, u.Name, "https:"+u.AvatarLink)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeGraphNegativeDepth(t *testing.T) {
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+
+	if _, err := s.NewClient().ScrapeGraph("luxas", -1, nil); err == nil {
+		t.Error("ScrapeGraph() with negative depth succeeded, want error")
+	}
+	if got := s.Requests("/luxas/followers"); got != 0 {
+		t.Errorf("fetched the followers %d times, want 0", got)
+	}
+}
+
+// testGraph returns a small graph, with a name that needs escaping in XML
+func testGraph() *speakerdeck.Graph {
+	return &speakerdeck.Graph{
+		Seed:  "luxas",
+		Depth: 1,
+		Nodes: []speakerdeck.GraphNode{
+			{
+				Author: speakerdeck.Author{
+					Name:       "Lucas Käldström",
+					Handle:     "luxas",
+					Link:       "https://speakerdeck.com/luxas",
+					AvatarLink: "https://secure.gravatar.com/avatar/luxas",
+				},
+			},
+			{
+				Author: speakerdeck.Author{
+					Name:   "Jane & <Doe>",
+					Handle: "janedoe",
+					Link:   "https://speakerdeck.com/janedoe",
+				},
+				Depth: 1,
+			},
+		},
+		Edges: []speakerdeck.GraphEdge{
+			{From: "janedoe", To: "luxas"},
+			{From: "luxas", To: "janedoe"},
+		},
+	}
+}
+
+func TestGraphWriteJSON(t *testing.T) {
+	g := testGraph()
+	buf := &bytes.Buffer{}
+	if err := g.WriteJSON(buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	got := &speakerdeck.Graph{}
+	if err := json.Unmarshal(buf.Bytes(), got); err != nil {
+		t.Fatalf("could not decode the written JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("decoded graph = %+v, want %+v", got, g)
+	}
+	for _, key := range []string{`"seed"`, `"nodes"`, `"edges"`, `"depth"`, `"from"`, `"to"`} {
+		if !strings.Contains(buf.String(), key) {
+			t.Errorf("written JSON doesn't contain %s:\n%s", key, buf)
+		}
+	}
+}
+
+func TestGraphWriteGraphML(t *testing.T) {
+	g := testGraph()
+	buf := &bytes.Buffer{}
+	if err := g.WriteGraphML(buf); err != nil {
+		t.Fatalf("WriteGraphML() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("written GraphML doesn't start with the XML header:\n%s", buf)
+	}
+
+	var doc struct {
+		Keys []struct {
+			ID  string `xml:"id,attr"`
+			For string `xml:"for,attr"`
+		} `xml:"key"`
+		Graph struct {
+			ID          string `xml:"id,attr"`
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("could not parse the written GraphML: %v", err)
+	}
+
+	if len(doc.Keys) != 4 {
+		t.Errorf("got %d keys, want 4", len(doc.Keys))
+	}
+	if doc.Graph.ID != g.Seed || doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("graph %q is %q, want %q to be directed", doc.Graph.ID, doc.Graph.EdgeDefault, g.Seed)
+	}
+	if len(doc.Graph.Nodes) != len(g.Nodes) {
+		t.Fatalf("got %d nodes, want %d", len(doc.Graph.Nodes), len(g.Nodes))
+	}
+	for i, n := range doc.Graph.Nodes {
+		want := map[string]string{
+			"name":       g.Nodes[i].Name,
+			"link":       g.Nodes[i].Link,
+			"avatarLink": g.Nodes[i].AvatarLink,
+			"depth":      strconv.Itoa(g.Nodes[i].Depth),
+		}
+		got := map[string]string{}
+		for _, d := range n.Data {
+			got[d.Key] = d.Value
+		}
+		if n.ID != g.Nodes[i].Handle || !reflect.DeepEqual(got, want) {
+			t.Errorf("node %q has data %q, want %q with %q", n.ID, got, g.Nodes[i].Handle, want)
+		}
+	}
+	if len(doc.Graph.Edges) != len(g.Edges) {
+		t.Fatalf("got %d edges, want %d", len(doc.Graph.Edges), len(g.Edges))
+	}
+	for i, e := range doc.Graph.Edges {
+		if e.Source != g.Edges[i].From || e.Target != g.Edges[i].To {
+			t.Errorf("edge %s->%s, want %s->%s", e.Source, e.Target, g.Edges[i].From, g.Edges[i].To)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package speakerdeck
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+// trackingParams are the query parameters removed from links when canonicalising them. Entries
+// ending with "*" match all parameters with that prefix.
+var trackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_cid",
+	"mc_eid",
+	"igshid",
+	"ref_src",
+	"si",
+}
+
+// extractLinks returns all links in e, both from its anchors and from the plain text outside of them.
+// The links are canonicalised and de-duplicated, and returned in the order they first appear. Only
+// http and https links are returned.
+func extractLinks(e *colly.HTMLElement) []Link {
+	links := []Link{}
+	seen := map[string]int{}
+	add := func(raw, text string) {
+		link, ok := canonicalLink(raw, e.Request.URL)
+		if !ok {
+			return
+		}
+		// Link texts that just repeat the link, as for autolinked text, carry no information
+		text = strings.TrimSpace(text)
+		if text == raw || text == link {
+			text = ""
+		}
+		if i, ok := seen[link]; ok {
+			if len(links[i].Text) == 0 {
+				links[i].Text = text
+			}
+			return
+		}
+		seen[link] = len(links)
+		links = append(links, Link{URL: link, Text: text})
+	}
+
+	e.DOM.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		add(href, a.Text())
+	})
+	// The text of the anchors has been handled above already
+	text := e.DOM.Clone()
+	text.Find("a").Remove()
+	text.Find("br").ReplaceWithHtml("\n")
+	for _, raw := range tokenizeLinks(text.Text()) {
+		add(raw, "")
+	}
+	return links
+}
+
+// tokenizeLinks returns the http and https links in text. A link extends over all characters allowed
+// in URIs by RFC 3986, except for trailing punctuation and unbalanced closing brackets, which are
+// considered part of the surrounding text, e.g. "(see https://example.com/a_(b))." yields
+// "https://example.com/a_(b)".
+func tokenizeLinks(text string) []string {
+	links := []string{}
+	lower := strings.ToLower(text)
+	for i := 0; i < len(text); {
+		start := nextLinkStart(lower, i)
+		if start < 0 {
+			break
+		}
+		end := start
+		for end < len(text) && isURIChar(text[end]) {
+			end++
+		}
+		link := trimLinkEnd(text[start:end])
+		// Skip bare schemes like "http://"
+		if !strings.HasSuffix(link, "://") {
+			links = append(links, link)
+		}
+		i = start + len(link)
+		if i <= start {
+			i = end
+		}
+	}
+	return links
+}
+
+// nextLinkStart returns the index of the next "http://" or "https://" in lowerText, starting from i
+// and not preceded by a letter, or -1 if there is none
+func nextLinkStart(lowerText string, i int) int {
+	for {
+		j := strings.Index(lowerText[i:], "http")
+		if j < 0 {
+			return -1
+		}
+		start := i + j
+		rest := lowerText[start:]
+		isLetter := start > 0 && isAlpha(lowerText[start-1])
+		if !isLetter && (strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://")) {
+			return start
+		}
+		i = start + len("http")
+	}
+}
+
+// trimLinkEnd removes trailing punctuation and unbalanced closing brackets from link
+func trimLinkEnd(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?'\"*", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		case last == ']' && strings.Count(link, "[") < strings.Count(link, "]"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+// isURIChar returns whether c is an unreserved, reserved or percent-encoding character of RFC 3986
+func isURIChar(c byte) bool {
+	return isAlpha(c) || (c >= '0' && c <= '9') || strings.IndexByte("-._~:/?#[]@!$&'()*+,;=%", c) >= 0
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// canonicalLink resolves raw relative to base, and returns its canonical form: the scheme and host
+// are lowercased, default ports and tracking parameters are removed, and an empty query or fragment
+// is dropped. false is returned for links that aren't http or https.
+func canonicalLink(raw string, base *url.URL) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return "", false
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+	if len(u.Path) == 0 {
+		u.Path = "/"
+	}
+
+	// Filter the raw query instead of re-encoding it, to keep the order and encoding of the parameters
+	params := []string{}
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if len(param) > 0 && !isTrackingParam(name) {
+			params = append(params, param)
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+	u.ForceQuery = false
+	return u.String(), true
+}
+
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range trackingParams {
+		if name == p || (strings.HasSuffix(p, "*") && strings.HasPrefix(name, strings.TrimSuffix(p, "*"))) {
+			return true
+		}
+	}
+	return false
+}
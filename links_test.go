@@ -0,0 +1,127 @@
+package speakerdeck
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "no links",
+			text: "Nothing to see here, not even http or https",
+			want: []string{},
+		},
+		{
+			name: "trailing punctuation",
+			text: "Slides at https://example.com/slides. Code at http://github.com/luxas, thanks!",
+			want: []string{"https://example.com/slides", "http://github.com/luxas"},
+		},
+		{
+			name: "balanced brackets are kept",
+			text: "(see https://en.wikipedia.org/wiki/Go_(programming_language))",
+			want: []string{"https://en.wikipedia.org/wiki/Go_(programming_language)"},
+		},
+		{
+			name: "unbalanced brackets are dropped",
+			text: "[https://example.com/a]",
+			want: []string{"https://example.com/a"},
+		},
+		{
+			name: "scheme is matched ignoring case",
+			text: "HTTPS://Example.com/A",
+			want: []string{"HTTPS://Example.com/A"},
+		},
+		{
+			name: "bare schemes and schemes within words are skipped",
+			text: "http:// and xhttps://example.com",
+			want: []string{},
+		},
+		{
+			name: "adjacent links",
+			text: "https://a.example.com\nhttps://b.example.com",
+			want: []string{"https://a.example.com", "https://b.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenizeLinks(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeLinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalLink(t *testing.T) {
+	base, _ := url.Parse("https://speakerdeck.com/luxas/talk")
+	tests := []struct {
+		name   string
+		raw    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "scheme and host are lowercased",
+			raw:    "HTTPS://GitHub.com/Luxas",
+			want:   "https://github.com/Luxas",
+			wantOK: true,
+		},
+		{
+			name:   "default port is removed",
+			raw:    "https://example.com:443/a",
+			want:   "https://example.com/a",
+			wantOK: true,
+		},
+		{
+			name:   "non-default port",
+			raw:    "https://example.com:8443/a",
+			want:   "https://example.com:8443/a",
+			wantOK: true,
+		},
+		{
+			name:   "empty path",
+			raw:    "https://example.com",
+			want:   "https://example.com/",
+			wantOK: true,
+		},
+		{
+			name:   "tracking parameters are removed",
+			raw:    "https://example.com/?b=2&utm_source=twitter&a=1&fbclid=x",
+			want:   "https://example.com/?b=2&a=1",
+			wantOK: true,
+		},
+		{
+			name:   "empty query is dropped",
+			raw:    "https://example.com/a?utm_medium=social",
+			want:   "https://example.com/a",
+			wantOK: true,
+		},
+		{
+			name:   "relative links are resolved",
+			raw:    "/c/technology",
+			want:   "https://speakerdeck.com/c/technology",
+			wantOK: true,
+		},
+		{
+			name:   "other schemes",
+			raw:    "mailto:luxas@example.com",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := canonicalLink(tt.raw, base)
+			if ok != tt.wantOK {
+				t.Fatalf("canonicalLink(%q) ok = %v, want %v", tt.raw, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("canonicalLink(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
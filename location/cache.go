@@ -0,0 +1,360 @@
+package location
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultGeocodeTTL is how long geocoding results are cached, unless CachingGeocoder.TTL is set
+	DefaultGeocodeTTL = 30 * 24 * time.Hour
+
+	// DefaultNegativeGeocodeTTL is how long it's cached that an address has no results, unless
+	// CachingGeocoder.NegativeTTL is set
+	DefaultNegativeGeocodeTTL = 24 * time.Hour
+)
+
+// CachedGeocode is a geocoding response stored in a GeocodeStore
+type CachedGeocode struct {
+	// Address is the address as requested, before normalisation
+	Address string `json:"address"`
+	// Hint is the hint the address was geocoded with, if any
+	Hint string `json:"hint,omitempty"`
+	// Results are the results of the Geocoder. Empty if the address had no results
+	Results []Result `json:"results"`
+	// Fetched is the time the results were returned by the Geocoder
+	Fetched time.Time `json:"fetched"`
+}
+
+// GeocodeStore stores responses for a CachingGeocoder. Implementations must be safe for concurrent use.
+type GeocodeStore interface {
+	// Get returns the response stored for key, if any
+	Get(key string) (*CachedGeocode, bool)
+	// Set stores entry for key
+	Set(key string, entry *CachedGeocode) error
+}
+
+var _ HintedGeocoder = &CachingGeocoder{}
+
+// NewCachingGeocoder creates a new CachingGeocoder caching the results of g in store. If store is nil,
+// the results are cached in memory.
+func NewCachingGeocoder(g Geocoder, store GeocodeStore) *CachingGeocoder {
+	if store == nil {
+		store = NewMemoryGeocodeStore(0)
+	}
+	return &CachingGeocoder{
+		Geocoder:  g,
+		Store:     store,
+		overrides: map[string]*CachedGeocode{},
+	}
+}
+
+// CachingGeocoder implements Geocoder by caching the results of another Geocoder, so that addresses that
+// are mentioned in many talks, or scraped many times, are only geocoded once. The addresses are cached
+// normalised, ignoring case, accents and punctuation. Errors are not cached.
+//
+// Places the Geocoder gets wrong can be pinned using Override, so that the Geocoder isn't asked at all.
+type CachingGeocoder struct {
+	// Geocoder is the Geocoder whose results are cached
+	Geocoder Geocoder
+
+	// Store is where the results are stored, e.g. NewMemoryGeocodeStore(...) or NewFileGeocodeStore(...)
+	Store GeocodeStore
+
+	// TTL is how long results are cached. If zero, DefaultGeocodeTTL is used. If negative, results never expire
+	TTL time.Duration
+
+	// NegativeTTL is how long addresses without results are cached. If zero, DefaultNegativeGeocodeTTL
+	// is used. If negative, addresses without results aren't cached at all
+	NegativeTTL time.Duration
+
+	mux       sync.RWMutex
+	overrides map[string]*CachedGeocode
+}
+
+// Override pins the results for address, so that they are returned instead of geocoding the address.
+// Results without a Confidence get a confidence of 1. If no results are given, the override is removed.
+func (c *CachingGeocoder) Override(address string, results ...Result) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	key := geocodeCacheKey(address)
+	if len(results) == 0 {
+		delete(c.overrides, key)
+		return
+	}
+	pinned := make([]Result, 0, len(results))
+	for _, r := range results {
+		if r.Confidence == 0 {
+			r.Confidence = 1
+		}
+		pinned = append(pinned, r)
+	}
+	c.overrides[key] = &CachedGeocode{Address: address, Results: pinned}
+}
+
+// LoadOverrides loads overrides from a JSON file mapping addresses to the pinned result, e.g.
+//
+//	{
+//	  "Bella Center, Copenhagen": {"formattedAddress": "Center Blvd. 5, 2300 København, Denmark", "lat": 55.6377, "lng": 12.5785}
+//	}
+func (c *CachingGeocoder) LoadOverrides(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	overrides := map[string]Result{}
+	if err := json.Unmarshal(b, &overrides); err != nil {
+		return fmt.Errorf("could not parse geocode overrides %q: %w", path, err)
+	}
+	for address, result := range overrides {
+		c.Override(address, result)
+	}
+	return nil
+}
+
+// Geocode implements Geocoder
+func (c *CachingGeocoder) Geocode(ctx context.Context, address string) ([]Result, error) {
+	return c.GeocodeHinted(ctx, address, "")
+}
+
+// GeocodeHinted implements HintedGeocoder. The same address is cached separately per hint, while
+// overrides apply regardless of the hint.
+func (c *CachingGeocoder) GeocodeHinted(ctx context.Context, address, hint string) ([]Result, error) {
+	key := geocodeCacheKey(address)
+	c.mux.RLock()
+	override, ok := c.overrides[key]
+	c.mux.RUnlock()
+	if ok {
+		return copyResults(override.Results), nil
+	}
+
+	if h := normalizeName(hint); len(h) > 0 {
+		key += "|" + h
+	}
+
+	if entry, ok := c.Store.Get(key); ok && !c.expired(entry) {
+		log.Debugf("Serving geocode results for %q from the cache", address)
+		return copyResults(entry.Results), nil
+	}
+
+	results, err := GeocodeHinted(ctx, c.Geocoder, address, hint)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 0 || c.ttl(c.NegativeTTL, DefaultNegativeGeocodeTTL) >= 0 {
+		entry := &CachedGeocode{Address: address, Hint: hint, Results: copyResults(results), Fetched: time.Now()}
+		if err := c.Store.Set(key, entry); err != nil {
+			log.Warnf("Could not cache the geocode results for %q: %v", address, err)
+		}
+	}
+	return results, nil
+}
+
+// expired returns whether entry is older than the TTL, or NegativeTTL for entries without results
+func (c *CachingGeocoder) expired(entry *CachedGeocode) bool {
+	ttl := c.ttl(c.TTL, DefaultGeocodeTTL)
+	if len(entry.Results) == 0 {
+		ttl = c.ttl(c.NegativeTTL, DefaultNegativeGeocodeTTL)
+	}
+	return ttl >= 0 && time.Since(entry.Fetched) > ttl
+}
+
+func (c *CachingGeocoder) ttl(ttl, def time.Duration) time.Duration {
+	if ttl == 0 {
+		return def
+	}
+	return ttl
+}
+
+// geocodeCacheKey returns the key addresses are cached by. Addresses differing only in case, accents
+// or punctuation share the key
+func geocodeCacheKey(address string) string {
+	return normalizeName(address)
+}
+
+func copyResults(results []Result) []Result {
+	return append([]Result{}, results...)
+}
+
+// NewMemoryGeocodeStore creates a GeocodeStore keeping at most maxEntries responses in memory. When full,
+// the least recently used response is evicted. If maxEntries is zero or less, the store is unbounded.
+func NewMemoryGeocodeStore(maxEntries int) GeocodeStore {
+	return &memoryGeocodeStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+type memoryGeocodeStore struct {
+	mux        sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru contains the *memoryGeocodeEntry values, the most recently used first
+	lru *list.List
+}
+
+type memoryGeocodeEntry struct {
+	key   string
+	entry *CachedGeocode
+}
+
+func (s *memoryGeocodeStore) Get(key string) (*CachedGeocode, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.lru.MoveToFront(elem)
+	return elem.Value.(*memoryGeocodeEntry).entry, true
+}
+
+func (s *memoryGeocodeStore) Set(key string, entry *CachedGeocode) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		elem.Value.(*memoryGeocodeEntry).entry = entry
+		s.lru.MoveToFront(elem)
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(&memoryGeocodeEntry{key, entry})
+
+	if s.maxEntries > 0 && s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryGeocodeEntry).key)
+	}
+	return nil
+}
+
+// DefaultGeocodeFlushDelay is how long NewFileGeocodeStore waits after a change before rewriting the file,
+// so that the addresses geocoded while scraping a page of talks are written at once
+const DefaultGeocodeFlushDelay = 5 * time.Second
+
+// FlushingGeocodeStore is a GeocodeStore that persists its responses in batches. Flush writes the
+// responses stored since the last write right away, e.g. before the program exits.
+type FlushingGeocodeStore interface {
+	GeocodeStore
+	Flush() error
+}
+
+var _ FlushingGeocodeStore = &fileGeocodeStore{}
+
+// NewFileGeocodeStore creates a GeocodeStore persisting the responses in a single JSON file at path,
+// which is loaded if it exists. As the whole file is rewritten when persisting, changes are batched:
+// the file is written DefaultGeocodeFlushDelay after the first change since the last write, or when
+// Flush is called. Responses stored after the last write are lost if the program exits without
+// calling Flush, which only means that they are geocoded again.
+func NewFileGeocodeStore(path string) (FlushingGeocodeStore, error) {
+	s := &fileGeocodeStore{
+		path:       path,
+		flushDelay: DefaultGeocodeFlushDelay,
+		entries:    map[string]*CachedGeocode{},
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.entries); err != nil {
+		return nil, fmt.Errorf("could not parse geocode cache %q: %w", path, err)
+	}
+	return s, nil
+}
+
+type fileGeocodeStore struct {
+	mux        sync.Mutex
+	path       string
+	flushDelay time.Duration
+	entries    map[string]*CachedGeocode
+	// dirty is true when entries have changed since the file was last written
+	dirty bool
+	// scheduled is true when a write is scheduled to happen after flushDelay
+	scheduled bool
+}
+
+func (s *fileGeocodeStore) Get(key string) (*CachedGeocode, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *fileGeocodeStore) Set(key string, entry *CachedGeocode) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.entries[key] = entry
+	s.dirty = true
+	if !s.scheduled {
+		s.scheduled = true
+		time.AfterFunc(s.flushDelay, func() {
+			s.mux.Lock()
+			s.scheduled = false
+			s.mux.Unlock()
+			if err := s.Flush(); err != nil {
+				log.Warnf("Could not write the geocode cache %q: %v", s.path, err)
+			}
+		})
+	}
+	return nil
+}
+
+// Flush writes the entries to the file, if they have changed since the last write
+func (s *fileGeocodeStore) Flush() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+	b, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, b); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// writeFileAtomic writes b to a temporary file next to path first, and then renames it to path, so that
+// the file is never left half-written
+func writeFileAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op after a successful rename
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
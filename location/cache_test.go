@@ -0,0 +1,389 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	bellaCenter = Result{FormattedAddress: "Center Blvd. 5, 2300 København, Denmark", Lat: 55.6377, Lng: 12.5785, Confidence: 1}
+	staleCenter = Result{FormattedAddress: "Bella Center, Copenhagen, Denmark", Lat: 55.6, Lng: 12.5, Confidence: 0.5}
+)
+
+func TestCachingGeocoderTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		negativeTTL time.Duration
+		// cached is the cached results, fetched age ago. If nil, nothing is cached
+		cached []Result
+		age    time.Duration
+		// known specifies whether the Geocoder knows the address
+		known       bool
+		wantCalls   int
+		wantResults []Result
+	}{
+		{
+			name:      "not cached",
+			known:     true,
+			wantCalls: 1, wantResults: []Result{bellaCenter},
+		},
+		{
+			name:   "fresh within the default TTL",
+			cached: []Result{staleCenter}, age: DefaultGeocodeTTL - time.Hour,
+			known:     true,
+			wantCalls: 0, wantResults: []Result{staleCenter},
+		},
+		{
+			name:   "expired after the default TTL",
+			cached: []Result{staleCenter}, age: DefaultGeocodeTTL + time.Hour,
+			known:     true,
+			wantCalls: 1, wantResults: []Result{bellaCenter},
+		},
+		{
+			name: "expired after a custom TTL",
+			ttl:  time.Hour, cached: []Result{staleCenter}, age: 2 * time.Hour,
+			known:     true,
+			wantCalls: 1, wantResults: []Result{bellaCenter},
+		},
+		{
+			name: "negative TTL never expires",
+			ttl:  -1, cached: []Result{staleCenter}, age: 10 * 365 * 24 * time.Hour,
+			known:     true,
+			wantCalls: 0, wantResults: []Result{staleCenter},
+		},
+		{
+			name:   "no results fresh within the default NegativeTTL",
+			cached: []Result{}, age: DefaultNegativeGeocodeTTL - time.Hour,
+			known:     true,
+			wantCalls: 0, wantResults: []Result{},
+		},
+		{
+			name:   "no results expired after the default NegativeTTL",
+			cached: []Result{}, age: DefaultNegativeGeocodeTTL + time.Hour,
+			known:     true,
+			wantCalls: 1, wantResults: []Result{bellaCenter},
+		},
+		{
+			name:        "no results expired after a custom NegativeTTL, while results would be fresh",
+			negativeTTL: time.Hour, cached: []Result{}, age: 2 * time.Hour,
+			known:     true,
+			wantCalls: 1, wantResults: []Result{bellaCenter},
+		},
+		{
+			name:      "unknown address",
+			wantCalls: 1, wantResults: []Result{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const address = "Bella Center, Copenhagen"
+			g := NewFakeGeocoder(nil)
+			if tt.known {
+				g.Set(address, bellaCenter)
+			}
+			store := NewMemoryGeocodeStore(0)
+			if tt.cached != nil {
+				entry := &CachedGeocode{Address: address, Results: tt.cached, Fetched: time.Now().Add(-tt.age)}
+				if err := store.Set(geocodeCacheKey(address), entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := NewCachingGeocoder(g, store)
+			c.TTL, c.NegativeTTL = tt.ttl, tt.negativeTTL
+
+			results, err := c.Geocode(context.Background(), address)
+			if err != nil {
+				t.Fatalf("Geocode() error = %v", err)
+			}
+			if !reflect.DeepEqual(results, tt.wantResults) {
+				t.Errorf("Geocode() = %v, want %v", results, tt.wantResults)
+			}
+			if got := g.Calls(address); got != tt.wantCalls {
+				t.Errorf("geocoded %d times, want %d", got, tt.wantCalls)
+			}
+
+			// Whatever was returned is now cached
+			if _, err := c.Geocode(context.Background(), address); err != nil {
+				t.Fatalf("Geocode() error = %v", err)
+			}
+			if got := g.Calls(address); got != tt.wantCalls {
+				t.Errorf("geocoded %d times after a cached Geocode, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachingGeocoderKeys(t *testing.T) {
+	errGeocode := errors.New("geocoding failed")
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		err         error
+		// addresses and hints are geocoded in order, with the hint of the same index
+		addresses []string
+		hints     []string
+		wantCalls int
+	}{
+		{
+			name:      "addresses are normalised",
+			addresses: []string{"Bella Center, Copenhagen", "bella center copenhagen", "  BELLA Center; Copenhagen!"},
+			wantCalls: 1,
+		},
+		{
+			name:      "accents are ignored",
+			addresses: []string{"Zürich", "Zurich"},
+			wantCalls: 1,
+		},
+		{
+			name:      "hints are cached separately",
+			addresses: []string{"Springfield", "Springfield", "Springfield", "Springfield"},
+			hints:     []string{"", "US", "us", "Illinois"},
+			wantCalls: 3,
+		},
+		{
+			name:        "addresses without results aren't cached with a negative NegativeTTL",
+			negativeTTL: -1,
+			addresses:   []string{"Nowhere", "Nowhere"},
+			wantCalls:   2,
+		},
+		{
+			name:      "errors aren't cached",
+			err:       errGeocode,
+			addresses: []string{"Bella Center", "Bella Center"},
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewFakeGeocoder(nil)
+			g.Err = tt.err
+			c := NewCachingGeocoder(g, nil)
+			c.NegativeTTL = tt.negativeTTL
+
+			for i, address := range tt.addresses {
+				hint := ""
+				if i < len(tt.hints) {
+					hint = tt.hints[i]
+				}
+				if _, err := c.GeocodeHinted(context.Background(), address, hint); !errors.Is(err, tt.err) {
+					t.Fatalf("GeocodeHinted(%q, %q) error = %v, want %v", address, hint, err, tt.err)
+				}
+			}
+			calls := 0
+			counted := map[string]bool{}
+			for _, address := range tt.addresses {
+				if !counted[fakeKey(address)] {
+					counted[fakeKey(address)] = true
+					calls += g.Calls(address)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("geocoded %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachingGeocoderOverride(t *testing.T) {
+	g := NewFakeGeocoder(map[string][]Result{"Bella Center, Copenhagen": {staleCenter}})
+	c := NewCachingGeocoder(g, nil)
+	ctx := context.Background()
+
+	pinned := bellaCenter
+	pinned.Confidence = 0
+	c.Override("Bella Center, Copenhagen", pinned)
+	for _, hint := range []string{"", "DK", "Sweden"} {
+		results, err := c.GeocodeHinted(ctx, "bella center copenhagen", hint)
+		if err != nil {
+			t.Fatalf("GeocodeHinted() error = %v", err)
+		}
+		if want := []Result{bellaCenter}; !reflect.DeepEqual(results, want) {
+			t.Errorf("GeocodeHinted() with hint %q = %v, want %v with confidence 1", hint, results, want)
+		}
+	}
+	if got := g.Calls("Bella Center, Copenhagen"); got != 0 {
+		t.Errorf("geocoded an overridden address %d times, want 0", got)
+	}
+
+	// Removing the override geocodes the address again
+	c.Override("Bella Center, Copenhagen")
+	results, err := c.Geocode(ctx, "Bella Center, Copenhagen")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if want := []Result{staleCenter}; !reflect.DeepEqual(results, want) {
+		t.Errorf("Geocode() after removing the override = %v, want %v", results, want)
+	}
+}
+
+func TestCachingGeocoderLoadOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// missing specifies that the overrides file doesn't exist
+		missing bool
+		wantErr bool
+		want    []Result
+	}{
+		{
+			name:    "valid",
+			content: `{"Bella Center, Copenhagen": {"formattedAddress": "Center Blvd. 5, 2300 København, Denmark", "lat": 55.6377, "lng": 12.5785}}`,
+			want:    []Result{bellaCenter},
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"Bella Center, Copenhagen": [`,
+			wantErr: true,
+			want:    []Result{staleCenter},
+		},
+		{
+			name:    "missing file",
+			missing: true,
+			wantErr: true,
+			want:    []Result{staleCenter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "overrides.json")
+			if !tt.missing {
+				if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			g := NewFakeGeocoder(map[string][]Result{"Bella Center, Copenhagen": {staleCenter}})
+			c := NewCachingGeocoder(g, nil)
+
+			if err := c.LoadOverrides(path); (err != nil) != tt.wantErr {
+				t.Fatalf("LoadOverrides() error = %v, want error %v", err, tt.wantErr)
+			}
+			results, err := c.Geocode(context.Background(), "Bella Center, Copenhagen")
+			if err != nil {
+				t.Fatalf("Geocode() error = %v", err)
+			}
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("Geocode() = %v, want %v", results, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryGeocodeStoreEviction(t *testing.T) {
+	s := NewMemoryGeocodeStore(2)
+	for _, key := range []string{"a", "b"} {
+		if err := s.Set(key, &CachedGeocode{Address: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Using "a" makes "b" the least recently used entry
+	if _, ok := s.Get("a"); !ok {
+		t.Fatal("entry a is missing")
+	}
+	if err := s.Set("c", &CachedGeocode{Address: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := s.Get(key); ok != want {
+			t.Errorf("entry %s cached = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestFileGeocodeStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "geocode.json")
+	ctx := context.Background()
+
+	// newGeocoder "restarts" the program, loading the cache from the file
+	newGeocoder := func() (*FakeGeocoder, *CachingGeocoder, FlushingGeocodeStore) {
+		store, err := NewFileGeocodeStore(path)
+		if err != nil {
+			t.Fatalf("NewFileGeocodeStore() error = %v", err)
+		}
+		g := NewFakeGeocoder(map[string][]Result{"Bella Center, Copenhagen": {bellaCenter}})
+		return g, NewCachingGeocoder(g, store), store
+	}
+
+	g, c, store := newGeocoder()
+	for _, address := range []string{"Bella Center, Copenhagen", "Nowhere"} {
+		if _, err := c.Geocode(ctx, address); err != nil {
+			t.Fatalf("Geocode() error = %v", err)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("the cache was written before the flush delay, error = %v", err)
+	}
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "geocode.json" {
+		t.Errorf("the cache directory contains %d files, want only geocode.json", len(files))
+	}
+
+	g, c, _ = newGeocoder()
+	results, err := c.Geocode(ctx, "Bella Center, Copenhagen")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if want := []Result{bellaCenter}; !reflect.DeepEqual(results, want) {
+		t.Errorf("Geocode() after a restart = %v, want %v", results, want)
+	}
+	if _, err := c.Geocode(ctx, "Nowhere"); err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	for _, address := range []string{"Bella Center, Copenhagen", "Nowhere"} {
+		if got := g.Calls(address); got != 0 {
+			t.Errorf("geocoded %q %d times after a restart, want 0", address, got)
+		}
+	}
+
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileGeocodeStore(path); err == nil {
+		t.Error("NewFileGeocodeStore() with a corrupt file succeeded, want error")
+	}
+}
+
+func TestFileGeocodeStoreBatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	store, err := NewFileGeocodeStore(path)
+	if err != nil {
+		t.Fatalf("NewFileGeocodeStore() error = %v", err)
+	}
+	store.(*fileGeocodeStore).flushDelay = 50 * time.Millisecond
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := store.Set(key, &CachedGeocode{Address: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for start := time.Now(); ; time.Sleep(5 * time.Millisecond) {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("the cache wasn't written after the flush delay")
+		}
+	}
+
+	loaded, err := NewFileGeocodeStore(path)
+	if err != nil {
+		t.Fatalf("NewFileGeocodeStore() error = %v", err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		if entry, ok := loaded.Get(key); !ok || entry.Address != key {
+			t.Errorf("entry %s = %v, %v after the batched write", key, entry, ok)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package location
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+var _ HintedGeocoder = &FakeGeocoder{}
+
+// NewFakeGeocoder creates a new FakeGeocoder returning the given results per address
+func NewFakeGeocoder(results map[string][]Result) *FakeGeocoder {
+	g := &FakeGeocoder{}
+	for address, res := range results {
+		g.Set(address, res...)
+	}
+	return g
+}
+
+// FakeGeocoder implements Geocoder using fixed, in-memory results, for use in tests. Addresses are
+// matched ignoring case and surrounding whitespace. Unknown addresses have no results. The zero value
+// is ready to use, and knows no addresses.
+type FakeGeocoder struct {
+	// Err, if set, is returned by all Geocode calls
+	Err error
+
+	mux     sync.Mutex
+	results map[string][]Result
+	calls   map[string]int
+	hints   map[string]string
+}
+
+// Set sets the results returned for address
+func (g *FakeGeocoder) Set(address string, results ...Result) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.init()
+	g.results[fakeKey(address)] = results
+}
+
+// init initializes the maps of g, if not done already. g.mux must be held
+func (g *FakeGeocoder) init() {
+	if g.results == nil {
+		g.results = map[string][]Result{}
+		g.calls = map[string]int{}
+		g.hints = map[string]string{}
+	}
+}
+
+// Calls returns how many times address has been geocoded
+func (g *FakeGeocoder) Calls(address string) int {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.calls[fakeKey(address)]
+}
+
+// Hint returns the hint address was last geocoded with
+func (g *FakeGeocoder) Hint(address string) string {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.hints[fakeKey(address)]
+}
+
+// Geocode implements Geocoder
+func (g *FakeGeocoder) Geocode(ctx context.Context, address string) ([]Result, error) {
+	return g.GeocodeHinted(ctx, address, "")
+}
+
+// GeocodeHinted implements HintedGeocoder. The hint is recorded, but doesn't affect the results.
+func (g *FakeGeocoder) GeocodeHinted(ctx context.Context, address, hint string) ([]Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.init()
+	g.calls[fakeKey(address)]++
+	g.hints[fakeKey(address)] = hint
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	return append([]Result{}, g.results[fakeKey(address)]...), nil
+}
+
+func fakeKey(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
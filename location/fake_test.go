@@ -0,0 +1,46 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFakeGeocoder(t *testing.T) {
+	ctx := context.Background()
+	helsinki := Result{FormattedAddress: "Helsinki, Finland", Lat: 60.17, Lng: 24.94, Confidence: 1}
+
+	tests := []struct {
+		name string
+		g    *FakeGeocoder
+	}{
+		{name: "zero value", g: &FakeGeocoder{}},
+		{name: "NewFakeGeocoder", g: NewFakeGeocoder(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.g
+			if results, err := g.Geocode(ctx, "Helsinki"); err != nil || len(results) != 0 {
+				t.Errorf("Geocode() of an unknown address = %v, %v, want no results", results, err)
+			}
+
+			g.Set("Helsinki", helsinki)
+			results, err := g.GeocodeHinted(ctx, "  HELSINKI ", "FI")
+			if err != nil {
+				t.Fatalf("GeocodeHinted() error = %v", err)
+			}
+			if want := []Result{helsinki}; !reflect.DeepEqual(results, want) {
+				t.Errorf("GeocodeHinted() = %v, want %v", results, want)
+			}
+			if calls, hint := g.Calls("helsinki"), g.Hint("helsinki"); calls != 2 || hint != "FI" {
+				t.Errorf("geocoded %d times, last with hint %q, want 2 times with FI", calls, hint)
+			}
+
+			g.Err = errors.New("quota exceeded")
+			if _, err := g.Geocode(ctx, "Helsinki"); !errors.Is(err, g.Err) {
+				t.Errorf("Geocode() error = %v, want %v", err, g.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,764 @@
+package location
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// DefaultFuzzyThreshold is the minimum similarity, from 0 to 1, of a misspelled name to a name in the
+	// Gazetteer for it to match, unless Gazetteer.FuzzyThreshold is set
+	DefaultFuzzyThreshold = 0.8
+
+	// maxGazetteerResults is the maximum amount of results returned by Gazetteer.Geocode
+	maxGazetteerResults = 10
+
+	// minFuzzyLength is the minimum length of names matched fuzzily, as short names are too easily
+	// similar to each other
+	minFuzzyLength = 4
+)
+
+// Place types of GazetteerEntries, as set by the loaders of the Gazetteer
+const (
+	PlaceTypeCountry = "country"
+	PlaceTypeRegion  = "region"
+	PlaceTypeCity    = "city"
+	PlaceTypeVenue   = "venue"
+	PlaceTypeOther   = "place"
+)
+
+var _ HintedGeocoder = &Gazetteer{}
+
+// GazetteerEntry describes a named place in a Gazetteer
+type GazetteerEntry struct {
+	// ID identifies the place in its dataset, e.g. "geonames:658225"
+	ID string
+
+	// Name is the name of the place, e.g. "Helsinki"
+	Name string
+
+	// Aliases are other names the place is known by, e.g. "Helsingfors"
+	Aliases []string
+
+	// Region is the first-level administrative division of the place, e.g. a state, if known
+	Region string
+
+	// RegionCode is the code of the first-level administrative division of the place within its country,
+	// e.g. "TX" or "18" in the GeoNames datasets. If Region isn't set, it's looked up by the code from
+	// the regions loaded using e.g. LoadGeoNamesRegions
+	RegionCode string
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country of the place, e.g. "FI"
+	CountryCode string
+
+	// Timezone is the IANA time zone of the place, e.g. "Europe/Helsinki", if known
+	Timezone string
+
+	// Type describes what kind of place this is, e.g. PlaceTypeCity
+	Type string
+
+	// Lat describes the latitude of the place
+	Lat float64
+
+	// Lng describes the longitude of the place
+	Lng float64
+
+	// Population is the amount of people living in the place, if known. When many places match an
+	// address equally well, the most populated one is preferred
+	Population int64
+}
+
+// NewGazetteer creates a new Gazetteer containing the given entries. More entries can be loaded from
+// files using e.g. LoadFile.
+func NewGazetteer(entries ...GazetteerEntry) *Gazetteer {
+	g := &Gazetteer{
+		names:        map[string][]int{},
+		nameLengths:  map[int][]string{},
+		timezones:    map[gridCell][]int{},
+		countries:    map[string]string{},
+		countryNames: map[string]string{},
+		regions:      map[string]string{},
+	}
+	g.Add(entries...)
+	return g
+}
+
+// LoadGazetteer creates a new Gazetteer, and loads the given files into it, see Gazetteer.LoadFile
+func LoadGazetteer(paths ...string) (*Gazetteer, error) {
+	g := NewGazetteer()
+	for _, path := range paths {
+		if err := g.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Gazetteer implements Geocoder offline, by looking up addresses in an in-memory index of known places,
+// e.g. loaded from the GeoNames datasets (https://download.geonames.org/export/dump/) or a CSV file
+// of conference venues.
+//
+// Addresses are split into their comma-separated parts, e.g. "Bella Center, Copenhagen, Denmark", and
+// the most specific part naming a known place is matched. The names are matched ignoring case, accents
+// and punctuation, and misspelled names are matched fuzzily if no name matches exactly. Places in the
+// country mentioned in the address, if any, are preferred.
+type Gazetteer struct {
+	// FuzzyThreshold is the minimum similarity, from 0 to 1, of a misspelled name to a known name for it
+	// to match. If zero, DefaultFuzzyThreshold is used. Set it above 1 to disable fuzzy matching
+	FuzzyThreshold float64
+
+	mux     sync.RWMutex
+	entries []GazetteerEntry
+	// names maps the normalised names and aliases to the indexes of the entries with that name
+	names map[string][]int
+	// nameLengths maps name lengths (in runes) to the normalised names of that length, for fuzzy matching
+	nameLengths map[int][]string
+	// countries maps the normalised names and codes of countries to their ISO codes
+	countries map[string]string
+	// countryNames maps the ISO codes of countries to their names
+	countryNames map[string]string
+	// regions maps region keys (see regionKey) to the names of the regions
+	regions map[string]string
+	// timezones maps grid cells to the indexes of the entries in them with a known time zone
+	timezones map[gridCell][]int
+}
+
+// Len returns the amount of places in the Gazetteer
+func (g *Gazetteer) Len() int {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	return len(g.entries)
+}
+
+// Add adds the given places to the Gazetteer. Places of type PlaceTypeCountry are also used to recognise
+// the country mentioned in addresses.
+func (g *Gazetteer) Add(entries ...GazetteerEntry) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	for _, e := range entries {
+		e.CountryCode = strings.ToUpper(e.CountryCode)
+		i := len(g.entries)
+		g.entries = append(g.entries, e)
+
+		for _, name := range append([]string{e.Name}, e.Aliases...) {
+			key := normalizeName(name)
+			if len(key) == 0 || containsIndex(g.names[key], i) {
+				continue
+			}
+			if _, ok := g.names[key]; !ok {
+				n := utf8.RuneCountInString(key)
+				g.nameLengths[n] = append(g.nameLengths[n], key)
+			}
+			g.names[key] = append(g.names[key], i)
+		}
+
+		if e.Type == PlaceTypeCountry && len(e.CountryCode) > 0 {
+			g.addCountry(e.CountryCode, e.Name, e.Aliases...)
+		}
+		// The first-order administrative divisions name the regions of their code, unless the
+		// names of the regions are loaded separately
+		if key := regionKey(e.CountryCode, e.RegionCode); e.Type == PlaceTypeRegion && len(key) > 0 {
+			if _, ok := g.regions[key]; !ok {
+				g.regions[key] = e.Name
+			}
+		}
+		// Countries span many time zones, so they only tell the time zone of their capital
+		if len(e.Timezone) > 0 && e.Type != PlaceTypeCountry {
+			cell := cellOf(e.Lat, e.Lng)
+			g.timezones[cell] = append(g.timezones[cell], i)
+		}
+	}
+}
+
+// addCountry registers the names of a country, for recognising countries in addresses
+func (g *Gazetteer) addCountry(code, name string, aliases ...string) {
+	code = strings.ToUpper(code)
+	if _, ok := g.countryNames[code]; !ok {
+		g.countryNames[code] = name
+	}
+	for _, n := range append([]string{code, name}, aliases...) {
+		if key := normalizeName(n); len(key) > 0 {
+			g.countries[key] = code
+		}
+	}
+}
+
+// regionKey returns the key of a region in Gazetteer.regions, e.g. "US.TX", or an empty string if either
+// code is unknown
+func regionKey(countryCode, regionCode string) string {
+	if len(countryCode) == 0 || len(regionCode) == 0 {
+		return ""
+	}
+	return strings.ToUpper(countryCode) + "." + strings.ToUpper(regionCode)
+}
+
+// region returns the name of the region of e, looking it up by its RegionCode if needed
+func (g *Gazetteer) region(e GazetteerEntry) string {
+	if len(e.Region) > 0 {
+		return e.Region
+	}
+	return g.regions[regionKey(e.CountryCode, e.RegionCode)]
+}
+
+// LoadFile loads places from the file at path into the Gazetteer. The format is chosen by the file name:
+// files ending with ".csv" are loaded using LoadCSV, files named like "countryInfo.txt" using
+// LoadGeoNamesCountries, files named like "admin1CodesASCII.txt" using LoadGeoNamesRegions, and all
+// other files using LoadGeoNames.
+func (g *Gazetteer) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(base, ".csv"):
+		err = g.LoadCSV(f)
+	case strings.HasPrefix(base, "countryinfo"):
+		err = g.LoadGeoNamesCountries(f)
+	case strings.HasPrefix(base, "admin1codes"):
+		err = g.LoadGeoNamesRegions(f)
+	default:
+		err = g.LoadGeoNames(f)
+	}
+	if err != nil {
+		return fmt.Errorf("could not load gazetteer %q: %w", path, err)
+	}
+	return nil
+}
+
+// LoadGeoNames loads places from a tab-separated GeoNames dataset, e.g. cities15000.txt or allCountries.txt.
+// Populated places get the type PlaceTypeCity, independent political entities PlaceTypeCountry, first-order
+// administrative divisions PlaceTypeRegion and all other features PlaceTypeOther. The regions of the places
+// are named by the loaded first-order administrative divisions, or by LoadGeoNamesRegions.
+func (g *Gazetteer) LoadGeoNames(r io.Reader) error {
+	entries := []GazetteerEntry{}
+	err := readTSV(r, func(line int, fields []string) error {
+		// See "geoname" in https://download.geonames.org/export/dump/readme.txt for the columns
+		if len(fields) < 15 {
+			return fmt.Errorf("line %d: expected at least 15 columns, got %d", line, len(fields))
+		}
+		lat, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid latitude: %w", line, err)
+		}
+		lng, err := strconv.ParseFloat(fields[5], 64)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid longitude: %w", line, err)
+		}
+		population, _ := strconv.ParseInt(fields[14], 10, 64)
+		timezone := ""
+		if len(fields) > 17 {
+			timezone = fields[17]
+		}
+
+		aliases := []string{}
+		if fields[2] != fields[1] {
+			aliases = append(aliases, fields[2])
+		}
+		if len(fields[3]) > 0 {
+			aliases = append(aliases, strings.Split(fields[3], ",")...)
+		}
+		entries = append(entries, GazetteerEntry{
+			ID:          "geonames:" + fields[0],
+			Name:        fields[1],
+			Aliases:     aliases,
+			RegionCode:  fields[10],
+			CountryCode: fields[8],
+			Timezone:    timezone,
+			Type:        geoNamesPlaceType(fields[6], fields[7]),
+			Lat:         lat,
+			Lng:         lng,
+			Population:  population,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	g.Add(entries...)
+	return nil
+}
+
+// geoNamesPlaceType returns the place type of a GeoNames feature class and code
+func geoNamesPlaceType(featureClass, featureCode string) string {
+	switch {
+	case featureClass == "P":
+		return PlaceTypeCity
+	case featureClass == "A" && strings.HasPrefix(featureCode, "PCL"):
+		return PlaceTypeCountry
+	case featureClass == "A" && featureCode == "ADM1":
+		return PlaceTypeRegion
+	default:
+		return PlaceTypeOther
+	}
+}
+
+// LoadGeoNamesCountries loads the names and codes of countries from the GeoNames countryInfo.txt file.
+// The file doesn't contain any coordinates, so no places are added, but the countries are recognised
+// in addresses, and their names are used in the formatted addresses of the results.
+func (g *Gazetteer) LoadGeoNamesCountries(r io.Reader) error {
+	type country struct{ code, iso3, name string }
+	countries := []country{}
+	err := readTSV(r, func(line int, fields []string) error {
+		if len(fields) < 5 {
+			return fmt.Errorf("line %d: expected at least 5 columns, got %d", line, len(fields))
+		}
+		countries = append(countries, country{
+			code: strings.TrimSpace(fields[0]),
+			iso3: strings.TrimSpace(fields[1]),
+			name: strings.TrimSpace(fields[4]),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	for _, c := range countries {
+		g.countryNames[strings.ToUpper(c.code)] = c.name
+		g.addCountry(c.code, c.name, c.iso3)
+	}
+	return nil
+}
+
+// LoadGeoNamesRegions loads the names of first-order administrative divisions, e.g. states, from the
+// GeoNames admin1CodesASCII.txt file. No places are added, but the regions of the places loaded using
+// LoadGeoNames are named, so that they can be told apart by the region given as hint.
+func (g *Gazetteer) LoadGeoNamesRegions(r io.Reader) error {
+	regions := map[string]string{}
+	err := readTSV(r, func(line int, fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("line %d: expected at least 2 columns, got %d", line, len(fields))
+		}
+		countryCode, regionCode, ok := strings.Cut(strings.TrimSpace(fields[0]), ".")
+		if !ok {
+			return fmt.Errorf("line %d: expected a code like \"US.TX\", got %q", line, fields[0])
+		}
+		if key := regionKey(countryCode, regionCode); len(key) > 0 {
+			regions[key] = strings.TrimSpace(fields[1])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	for key, name := range regions {
+		g.regions[key] = name
+	}
+	return nil
+}
+
+// readTSV calls fn for every line of tab-separated values in r, skipping empty lines and "#" comments
+func readTSV(r io.Reader, fn func(line int, fields []string) error) error {
+	s := bufio.NewScanner(r)
+	// The alternate names of large cities make for very long lines
+	s.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if len(strings.TrimSpace(text)) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := fn(line, strings.Split(text, "\t")); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// LoadCSV loads places from a CSV file with a header row naming the columns. The name, lat and lng (or lon)
+// columns are required, while the id, country (an ISO 3166-1 alpha-2 code), region, regioncode, timezone,
+// aliases (separated by ";"), type and population columns are optional. The type defaults to PlaceTypeVenue, e.g.
+//
+//	name,aliases,lat,lng,country
+//	Bella Center,Bella Center Copenhagen;BC,55.6377,12.5785,DK
+func (g *Gazetteer) LoadCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("could not read the CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["lng"]; !ok {
+		if i, ok := columns["lon"]; ok {
+			columns["lng"] = i
+		}
+	}
+	for _, required := range []string{"name", "lat", "lng"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("the CSV header lacks the mandatory %q column", required)
+		}
+	}
+
+	entries := []GazetteerEntry{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		column := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		lat, err := strconv.ParseFloat(column("lat"), 64)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid latitude: %w", line, err)
+		}
+		lng, err := strconv.ParseFloat(column("lng"), 64)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid longitude: %w", line, err)
+		}
+		e := GazetteerEntry{
+			ID:          column("id"),
+			Name:        column("name"),
+			Region:      column("region"),
+			RegionCode:  column("regioncode"),
+			CountryCode: column("country"),
+			Timezone:    column("timezone"),
+			Type:        column("type"),
+			Lat:         lat,
+			Lng:         lng,
+		}
+		if len(e.Name) == 0 {
+			return fmt.Errorf("line %d: the name is mandatory", line)
+		}
+		if len(e.Type) == 0 {
+			e.Type = PlaceTypeVenue
+		}
+		for _, alias := range strings.Split(column("aliases"), ";") {
+			if alias = strings.TrimSpace(alias); len(alias) > 0 {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+		if p := column("population"); len(p) > 0 {
+			if e.Population, err = strconv.ParseInt(p, 10, 64); err != nil {
+				return fmt.Errorf("line %d: invalid population: %w", line, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	g.Add(entries...)
+	return nil
+}
+
+// gazetteerQuery is a parsed address to look up in a Gazetteer
+type gazetteerQuery struct {
+	// whole is the whole normalised address
+	whole string
+	// parts are the normalised comma-separated parts of the address
+	parts []string
+	// country is the code of the country mentioned in the address or hint, if any
+	country string
+	// region is the normalised region name or code given as hint, if any
+	region string
+}
+
+// gazetteerMatch is a candidate place for an address
+type gazetteerMatch struct {
+	index      int
+	confidence float64
+}
+
+// Geocode implements Geocoder
+func (g *Gazetteer) Geocode(ctx context.Context, address string) ([]Result, error) {
+	return g.GeocodeHinted(ctx, address, "")
+}
+
+// GeocodeHinted implements HintedGeocoder. A hint naming a known country (or its code) is used like a country
+// mentioned in the address, unless the address mentions a country itself. Other hints prefer places in
+// the region of that name or code, e.g. "Texas" or "TX", if the regions of the places are known.
+func (g *Gazetteer) GeocodeHinted(ctx context.Context, address, hint string) ([]Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	q := &gazetteerQuery{}
+	for _, part := range strings.Split(address, ",") {
+		if part = normalizeName(part); len(part) > 0 {
+			q.parts = append(q.parts, part)
+		}
+	}
+	if len(q.parts) == 0 {
+		return []Result{}, nil
+	}
+	// The whole address may be a name in itself, e.g. "Washington, D.C."
+	q.whole = strings.Join(q.parts, " ")
+
+	// Countries are usually mentioned last, e.g. "Barcelona, Spain"
+	for i := len(q.parts) - 1; i >= 0 && len(q.country) == 0; i-- {
+		q.country = g.countries[q.parts[i]]
+	}
+	if country, ok := g.countries[normalizeName(hint)]; ok {
+		if len(q.country) == 0 {
+			q.country = country
+		}
+	} else {
+		q.region = normalizeName(hint)
+	}
+
+	matches := g.match(ctx, q, g.exactMatches)
+	if len(matches) == 0 && g.fuzzyThreshold() <= 1 {
+		matches = g.match(ctx, q, g.fuzzyMatches)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.confidence != b.confidence {
+			return a.confidence > b.confidence
+		}
+		return g.entries[a.index].Population > g.entries[b.index].Population
+	})
+	if len(matches) > maxGazetteerResults {
+		matches = matches[:maxGazetteerResults]
+	}
+
+	results := make([]Result, 0, len(matches))
+	for _, m := range matches {
+		e := g.entries[m.index]
+		r := Result{
+			FormattedAddress: g.formatAddress(e),
+			Lat:              e.Lat,
+			Lng:              e.Lng,
+			Region:           g.region(e),
+			Country:          g.countryNames[e.CountryCode],
+			CountryCode:      e.CountryCode,
+			PlaceID:          e.ID,
+			Confidence:       m.confidence,
+		}
+		if e.Type == PlaceTypeCity {
+			r.City = e.Name
+		}
+		if len(e.Type) > 0 {
+			r.Types = []string{e.Type}
+		}
+		// Countries span many time zones, so their time zone can't be told
+		if e.Type != PlaceTypeCountry {
+			r.Timezone = e.Timezone
+			if len(r.Timezone) == 0 {
+				r.Timezone = g.timezone(e.Lat, e.Lng)
+			}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// match returns the places matching the whole address, or else the most specific part of it that matches
+// any places using matchName. The confidence of the places is lowered if only a part of the address matched,
+// or if they're not in the country mentioned in the address or the hinted region.
+func (g *Gazetteer) match(ctx context.Context, q *gazetteerQuery, matchName func(context.Context, string) map[int]float64) []gazetteerMatch {
+	candidates := []string{q.whole}
+	if len(q.parts) > 1 {
+		candidates = append(candidates, q.parts...)
+	}
+	country := q.country
+
+	for i, name := range candidates {
+		found := matchName(ctx, name)
+		if len(found) == 0 {
+			continue
+		}
+
+		matches := make([]gazetteerMatch, 0, len(found))
+		for index, confidence := range found {
+			e := g.entries[index]
+			if i > 0 {
+				confidence *= 0.9
+			}
+			switch {
+			case len(country) == 0:
+			case e.CountryCode == country && g.countries[name] == country:
+				// Only the country itself matched, which doesn't make it any more certain
+			case e.CountryCode == country:
+				confidence += 0.1
+			default:
+				confidence *= 0.5
+			}
+			// Places whose region isn't known can't be told apart by the hinted region
+			if len(q.region) > 0 && !g.inRegion(e, q.region) {
+				confidence *= 0.8
+			}
+			if confidence > 1 {
+				confidence = 1
+			}
+			matches = append(matches, gazetteerMatch{index: index, confidence: confidence})
+		}
+		return matches
+	}
+	return nil
+}
+
+// inRegion returns whether e is in the region with the normalised name or code region. Places whose region
+// isn't known are considered to be in any region
+func (g *Gazetteer) inRegion(e GazetteerEntry, region string) bool {
+	name := g.region(e)
+	if len(name) == 0 {
+		return true
+	}
+	return normalizeName(name) == region || normalizeName(e.RegionCode) == region
+}
+
+// exactMatches returns the places named name, all with similarity 1
+func (g *Gazetteer) exactMatches(_ context.Context, name string) map[int]float64 {
+	found := map[int]float64{}
+	for _, i := range g.names[name] {
+		found[i] = 1
+	}
+	return found
+}
+
+// fuzzyMatches returns the places with a name similar to name, with the best similarity of their names
+func (g *Gazetteer) fuzzyMatches(ctx context.Context, name string) map[int]float64 {
+	found := map[int]float64{}
+	n := utf8.RuneCountInString(name)
+	if n < minFuzzyLength {
+		return found
+	}
+	threshold := g.fuzzyThreshold()
+	// Names differing in length by more than this can't be similar enough
+	maxDistance := int(float64(n) * (1 - threshold) / threshold)
+
+	for length := n - maxDistance; length <= n+maxDistance; length++ {
+		if ctx.Err() != nil {
+			return found
+		}
+		for _, key := range g.nameLengths[length] {
+			similarity := nameSimilarity(name, key)
+			if similarity < threshold {
+				continue
+			}
+			for _, i := range g.names[key] {
+				if similarity > found[i] {
+					found[i] = similarity
+				}
+			}
+		}
+	}
+	return found
+}
+
+func (g *Gazetteer) fuzzyThreshold() float64 {
+	if g.FuzzyThreshold == 0 {
+		return DefaultFuzzyThreshold
+	}
+	return g.FuzzyThreshold
+}
+
+// formatAddress returns the name of the place, followed by the name (or else code) of its country
+func (g *Gazetteer) formatAddress(e GazetteerEntry) string {
+	if e.Type == PlaceTypeCountry || len(e.CountryCode) == 0 {
+		return e.Name
+	}
+	if name, ok := g.countryNames[e.CountryCode]; ok {
+		return e.Name + ", " + name
+	}
+	return e.Name + ", " + e.CountryCode
+}
+
+// normalizer removes the accents of letters, e.g. "Zürich" becomes "Zurich"
+var normalizer = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// normalizeName returns the lowercase form of name without accents and punctuation, and with all
+// whitespace collapsed to single spaces
+func normalizeName(name string) string {
+	if folded, _, err := transform.String(normalizer, name); err == nil {
+		name = folded
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// nameSimilarity returns the similarity of a and b from 0 to 1, based on their Levenshtein distance
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the amount of single-rune insertions, deletions and substitutions needed to turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, index := range indexes {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package location
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const (
+	testCities = "658225\tHelsinki\tHelsinki\tHelsingfors,Khel sinki\t60.16952\t24.93545\tP\tPPLC\tFI\t\t18\t091\t\t\t558457\t\t26\tEurope/Helsinki\t2019-01-01\n" +
+		"2618425\tCopenhagen\tCopenhagen\tKobenhavn,København\t55.67594\t12.56553\tP\tPPLC\tDK\t\t17\t101\t\t\t1153615\t\t14\tEurope/Copenhagen\t2019-01-01\n" +
+		"3128760\tBarcelona\tBarcelona\tBarna\t41.38879\t2.15899\tP\tPPLA\tES\t\t56\tB\t\t\t1621537\t\t15\tEurope/Madrid\t2019-01-01\n" +
+		"4920423\tBarcelona\tBarcelona\t\t39.9\t-84.5\tP\tPPL\tUS\t\tOH\t\t\t\t100\t\t\tAmerica/New_York\t2019-01-01\n" +
+		"4250542\tSpringfield\tSpringfield\t\t39.80172\t-89.64371\tP\tPPLA\tUS\t\tIL\t167\t\t\t116250\t\t180\tAmerica/Chicago\t2019-01-01\n" +
+		"4409896\tSpringfield\tSpringfield\t\t37.21533\t-93.29824\tP\tPPLA2\tUS\t\tMO\t077\t\t\t166810\t\t393\tAmerica/Chicago\t2019-01-01\n" +
+		"2510769\tSpain\tSpain\tEspana,España\t40.0\t-4.0\tA\tPCLI\tES\t\t00\t\t\t\t46723749\t\t\tEurope/Madrid\t2019-01-01\n"
+	testCountries = "#ISO\tISO3\tISO-Numeric\tfips\tCountry\n" +
+		"FI\tFIN\t246\tFI\tFinland\n" +
+		"DK\tDNK\t208\tDA\tDenmark\n" +
+		"ES\tESP\t724\tSP\tSpain\n" +
+		"US\tUSA\t840\tUS\tUnited States\n"
+	testRegions = "US.IL\tIllinois\tIllinois\t4896861\n" +
+		"US.MO\tMissouri\tMissouri\t4398678\n" +
+		"FI.18\tUusimaa\tUusimaa\t830709\n"
+	testVenues = "id,name,aliases,lat,lng,country,region\n" +
+		"venue-1,Fira Gran Via,Fira Barcelona,41.354,2.128,ES,Catalonia\n" +
+		"venue-2,Bella Center,Bella Center Copenhagen;BC,55.6377,12.5785,DK,\n"
+)
+
+func testGazetteer(t *testing.T) *Gazetteer {
+	g := NewGazetteer()
+	for _, load := range []struct {
+		fn   func(r *strings.Reader) error
+		data string
+	}{
+		{func(r *strings.Reader) error { return g.LoadGeoNames(r) }, testCities},
+		{func(r *strings.Reader) error { return g.LoadGeoNamesCountries(r) }, testCountries},
+		{func(r *strings.Reader) error { return g.LoadGeoNamesRegions(r) }, testRegions},
+		{func(r *strings.Reader) error { return g.LoadCSV(r) }, testVenues},
+	} {
+		if err := load.fn(strings.NewReader(load.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestGazetteerGeocode(t *testing.T) {
+	g := testGazetteer(t)
+	if g.Len() != 9 {
+		t.Fatalf("Len() = %d, want 9", g.Len())
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		hint    string
+		// wantID is the PlaceID of the best result, or empty if nothing should match
+		wantID           string
+		wantAddress      string
+		wantRegion       string
+		wantTimezone     string
+		wantMinConfident float64
+	}{
+		{
+			name:             "name",
+			address:          "Helsinki",
+			wantID:           "geonames:658225",
+			wantAddress:      "Helsinki, Finland",
+			wantRegion:       "Uusimaa",
+			wantTimezone:     "Europe/Helsinki",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "alias with accents",
+			address:          "KØBENHAVN",
+			wantID:           "geonames:2618425",
+			wantAddress:      "Copenhagen, Denmark",
+			wantTimezone:     "Europe/Copenhagen",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "most populated by default",
+			address:          "Barcelona",
+			wantID:           "geonames:3128760",
+			wantAddress:      "Barcelona, Spain",
+			wantTimezone:     "Europe/Madrid",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "country in the address",
+			address:          "Barcelona, United States",
+			wantID:           "geonames:4920423",
+			wantAddress:      "Barcelona, United States",
+			wantTimezone:     "America/New_York",
+			wantMinConfident: 0.9,
+		},
+		{
+			name:             "country as hint",
+			address:          "Barcelona",
+			hint:             "US",
+			wantID:           "geonames:4920423",
+			wantAddress:      "Barcelona, United States",
+			wantTimezone:     "America/New_York",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "region name as hint",
+			address:          "Springfield",
+			hint:             "Illinois",
+			wantID:           "geonames:4250542",
+			wantAddress:      "Springfield, United States",
+			wantRegion:       "Illinois",
+			wantTimezone:     "America/Chicago",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "region code as hint",
+			address:          "Springfield",
+			hint:             "MO",
+			wantID:           "geonames:4409896",
+			wantAddress:      "Springfield, United States",
+			wantRegion:       "Missouri",
+			wantTimezone:     "America/Chicago",
+			wantMinConfident: 1,
+		},
+		{
+			name:             "venue from CSV",
+			address:          "Fira Gran Via, Av. Joan Carles I, Barcelona, Spain",
+			wantID:           "venue-1",
+			wantAddress:      "Fira Gran Via, Spain",
+			wantRegion:       "Catalonia",
+			wantTimezone:     "Europe/Madrid",
+			wantMinConfident: 0.9,
+		},
+		{
+			name:             "misspelled",
+			address:          "Bella Centre",
+			wantID:           "venue-2",
+			wantAddress:      "Bella Center, Denmark",
+			wantTimezone:     "Europe/Copenhagen",
+			wantMinConfident: 0.8,
+		},
+		{
+			name:    "unknown",
+			address: "Atlantis",
+		},
+		{
+			name:    "empty",
+			address: " , ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := g.GeocodeHinted(context.Background(), tt.address, tt.hint)
+			if err != nil {
+				t.Fatalf("GeocodeHinted() error = %v", err)
+			}
+			if len(tt.wantID) == 0 {
+				if len(results) != 0 {
+					t.Errorf("got %d results, want none: %+v", len(results), results)
+				}
+				return
+			}
+			if len(results) == 0 {
+				t.Fatalf("got no results, want %q", tt.wantID)
+			}
+			r := results[0]
+			if r.PlaceID != tt.wantID || r.FormattedAddress != tt.wantAddress {
+				t.Errorf("best result = %q (%q), want %q (%q)", r.PlaceID, r.FormattedAddress, tt.wantID, tt.wantAddress)
+			}
+			if r.Region != tt.wantRegion || r.Timezone != tt.wantTimezone {
+				t.Errorf("region and time zone = %q, %q, want %q, %q", r.Region, r.Timezone, tt.wantRegion, tt.wantTimezone)
+			}
+			if r.Confidence < tt.wantMinConfident || r.Confidence > 1 {
+				t.Errorf("confidence = %v, want at least %v", r.Confidence, tt.wantMinConfident)
+			}
+			for _, other := range results[1:] {
+				if other.Confidence > r.Confidence {
+					t.Errorf("result %q is more confident than the best result", other.PlaceID)
+				}
+			}
+		})
+	}
+}
+
+func TestGazetteerFuzzyThreshold(t *testing.T) {
+	g := testGazetteer(t)
+	tests := []struct {
+		threshold float64
+		address   string
+		wantMatch bool
+	}{
+		{address: "Copenhgen", wantMatch: true},
+		{address: "Kopenhagen", wantMatch: true},
+		{address: "Hel", wantMatch: false},
+		{threshold: 0.95, address: "Copenhgen", wantMatch: false},
+		{threshold: 2, address: "Copenhgen", wantMatch: false},
+	}
+	for _, tt := range tests {
+		g.FuzzyThreshold = tt.threshold
+		results, err := g.Geocode(context.Background(), tt.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(results) > 0; got != tt.wantMatch {
+			t.Errorf("Geocode(%q) with threshold %v matched = %v, want %v", tt.address, tt.threshold, got, tt.wantMatch)
+		}
+	}
+}
+
+func TestGazetteerTimezone(t *testing.T) {
+	g := testGazetteer(t)
+	tests := []struct {
+		name     string
+		lat, lng float64
+		want     string
+	}{
+		{name: "in the city", lat: 60.17, lng: 24.94, want: "Europe/Helsinki"},
+		{name: "nearby", lat: 60.3, lng: 25.5, want: "Europe/Helsinki"},
+		{name: "nearest of two", lat: 38.5, lng: -92, want: "America/Chicago"},
+		{name: "too far away", lat: 0, lng: -150, want: ""},
+	}
+	for _, tt := range tests {
+		if got := g.Timezone(tt.lat, tt.lng); got != tt.want {
+			t.Errorf("%s: Timezone(%v, %v) = %q, want %q", tt.name, tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestGazetteerLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		load func(g *Gazetteer) error
+	}{
+		{"GeoNames with too few columns", func(g *Gazetteer) error {
+			return g.LoadGeoNames(strings.NewReader("658225\tHelsinki\n"))
+		}},
+		{"GeoNames with an invalid latitude", func(g *Gazetteer) error {
+			return g.LoadGeoNames(strings.NewReader(strings.Replace(testCities, "60.16952", "north", 1)))
+		}},
+		{"CSV without coordinates", func(g *Gazetteer) error {
+			return g.LoadCSV(strings.NewReader("name,lat\nBella Center,55.6377\n"))
+		}},
+		{"CSV without a name", func(g *Gazetteer) error {
+			return g.LoadCSV(strings.NewReader("name,lat,lng\n,55.6377,12.5785\n"))
+		}},
+		{"regions without a country code", func(g *Gazetteer) error {
+			return g.LoadGeoNamesRegions(strings.NewReader("TX\tTexas\n"))
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.load(NewGazetteer()); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package location
+
+import (
+	"context"
+)
+
+// Geocoder resolves addresses into geographical coordinates. Implementations exist for the Google Maps
+// Geocoding API (GoogleGeocoder), OpenStreetMap Nominatim (NominatimGeocoder) and for tests (FakeGeocoder).
+type Geocoder interface {
+	// Geocode returns the places matching address, the best match first. No results and a nil error
+	// is returned if nothing matches address.
+	Geocode(ctx context.Context, address string) ([]Result, error)
+}
+
+// Result describes a place returned by a Geocoder
+type Result struct {
+	// FormattedAddress is the official street address (or similar) of the place
+	FormattedAddress string `json:"formattedAddress"`
+
+	// Lat describes the latitude of the place
+	Lat float64 `json:"lat"`
+
+	// Lng describes the longitude of the place
+	Lng float64 `json:"lng"`
+
+	// City is the city (or similar locality) of the place, if known
+	City string `json:"city,omitempty"`
+
+	// Region is the first-level administrative division of the place, e.g. a state, if known
+	Region string `json:"region,omitempty"`
+
+	// Country is the name of the country of the place, if known
+	Country string `json:"country,omitempty"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country of the place, e.g. "FI", if known
+	CountryCode string `json:"countryCode,omitempty"`
+
+	// PlaceID identifies the place in the dataset of the Geocoder, if known
+	PlaceID string `json:"placeID,omitempty"`
+
+	// Types describes what kind of place this is. The types depend on the Geocoder
+	Types []string `json:"types,omitempty"`
+
+	// Timezone is the IANA time zone of the place, if known by the Geocoder
+	Timezone string `json:"timezone,omitempty"`
+
+	// Confidence describes how well the place matches the requested address, from 0 to 1. Geocoders
+	// that don't tell how well their results match set it to 1
+	Confidence float64 `json:"confidence"`
+}
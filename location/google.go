@@ -0,0 +1,92 @@
+package location
+
+import (
+	"context"
+	"strings"
+
+	"googlemaps.github.io/maps"
+)
+
+var _ HintedGeocoder = &GoogleGeocoder{}
+
+// NewGoogleGeocoder creates a new GoogleGeocoder using a Google Maps API Key with access
+// to the Geocoding API.
+func NewGoogleGeocoder(apiKey string) (*GoogleGeocoder, error) {
+	c, err := maps.NewClient(maps.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleGeocoder{c}, nil
+}
+
+// GoogleGeocoder implements Geocoder using the Google Maps Geocoding API
+type GoogleGeocoder struct {
+	c *maps.Client
+}
+
+// Geocode implements Geocoder
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) ([]Result, error) {
+	return g.GeocodeHinted(ctx, address, "")
+}
+
+// GeocodeHinted implements HintedGeocoder. Country codes bias the results using region biasing, while
+// other hints are made part of the address.
+func (g *GoogleGeocoder) GeocodeHinted(ctx context.Context, address, hint string) ([]Result, error) {
+	r := &maps.GeocodingRequest{
+		Address: address,
+	}
+	switch {
+	case strings.EqualFold(hint, "GB"):
+		// The regions are ccTLDs, which differ from the country code for the United Kingdom
+		r.Region = "uk"
+	case isCountryCode(hint):
+		r.Region = strings.ToLower(hint)
+	default:
+		r.Address = hintedAddress(address, hint)
+	}
+	results, err := g.c.Geocode(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Result, 0, len(results))
+	for _, result := range results {
+		r := Result{
+			FormattedAddress: result.FormattedAddress,
+			Lat:              result.Geometry.Location.Lat,
+			Lng:              result.Geometry.Location.Lng,
+			PlaceID:          result.PlaceID,
+			Types:            result.Types,
+			Confidence:       1,
+		}
+		// Partial matches only match a part of the address, e.g. the city but not the street
+		if result.PartialMatch {
+			r.Confidence = 0.5
+		}
+		for _, c := range result.AddressComponents {
+			switch {
+			case hasType(c.Types, "country"):
+				r.Country = c.LongName
+				r.CountryCode = c.ShortName
+			case hasType(c.Types, "administrative_area_level_1"):
+				r.Region = c.LongName
+			case hasType(c.Types, "locality"):
+				r.City = c.LongName
+			case hasType(c.Types, "postal_town") && len(r.City) == 0:
+				// Some countries, e.g. the UK, have postal towns instead of localities
+				r.City = c.LongName
+			}
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func hasType(types []string, t string) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
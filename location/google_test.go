@@ -0,0 +1,127 @@
+package location
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"googlemaps.github.io/maps"
+)
+
+const googleResponse = `{
+  "status": "OK",
+  "results": [
+    {
+      "formatted_address": "Center Blvd. 5, 2300 København, Denmark",
+      "geometry": {"location": {"lat": 55.6377, "lng": 12.5785}},
+      "place_id": "ChIJbellacenter",
+      "types": ["establishment", "point_of_interest"],
+      "address_components": [
+        {"long_name": "5", "short_name": "5", "types": ["street_number"]},
+        {"long_name": "København", "short_name": "København", "types": ["locality", "political"]},
+        {"long_name": "Region Hovedstaden", "short_name": "Region Hovedstaden", "types": ["administrative_area_level_1", "political"]},
+        {"long_name": "Denmark", "short_name": "DK", "types": ["country", "political"]}
+      ]
+    },
+    {
+      "formatted_address": "Manchester M2 3GX, UK",
+      "geometry": {"location": {"lat": 53.4763, "lng": -2.2463}},
+      "place_id": "ChIJmanchester",
+      "types": ["postal_code"],
+      "partial_match": true,
+      "address_components": [
+        {"long_name": "Manchester", "short_name": "Manchester", "types": ["postal_town"]},
+        {"long_name": "England", "short_name": "England", "types": ["administrative_area_level_1", "political"]},
+        {"long_name": "United Kingdom", "short_name": "GB", "types": ["country", "political"]}
+      ]
+    }
+  ]
+}`
+
+// testGoogleGeocoder returns a GoogleGeocoder using a test server, and a function returning the
+// query parameters of the last request
+func testGoogleGeocoder(t *testing.T) (*GoogleGeocoder, func() map[string]string) {
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{}
+		for key := range r.URL.Query() {
+			query[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(googleResponse))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := maps.NewClient(maps.WithAPIKey("AIza-test"), maps.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &GoogleGeocoder{c}, func() map[string]string { return query }
+}
+
+func TestGoogleGeocoder(t *testing.T) {
+	g, _ := testGoogleGeocoder(t)
+	results, err := g.Geocode(context.Background(), "Bella Center")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	want := []Result{
+		{
+			FormattedAddress: "Center Blvd. 5, 2300 København, Denmark",
+			Lat:              55.6377,
+			Lng:              12.5785,
+			City:             "København",
+			Region:           "Region Hovedstaden",
+			Country:          "Denmark",
+			CountryCode:      "DK",
+			PlaceID:          "ChIJbellacenter",
+			Types:            []string{"establishment", "point_of_interest"},
+			Confidence:       1,
+		},
+		{
+			FormattedAddress: "Manchester M2 3GX, UK",
+			Lat:              53.4763,
+			Lng:              -2.2463,
+			City:             "Manchester",
+			Region:           "England",
+			Country:          "United Kingdom",
+			CountryCode:      "GB",
+			PlaceID:          "ChIJmanchester",
+			Types:            []string{"postal_code"},
+			// Partial matches are less certain
+			Confidence: 0.5,
+		},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Geocode() = %+v, want %+v", results, want)
+	}
+}
+
+func TestGoogleGeocoderHints(t *testing.T) {
+	tests := []struct {
+		hint        string
+		wantAddress string
+		wantRegion  string
+	}{
+		{hint: "", wantAddress: "Springfield"},
+		{hint: "US", wantAddress: "Springfield", wantRegion: "us"},
+		{hint: "fi", wantAddress: "Springfield", wantRegion: "fi"},
+		// The region is a ccTLD, which is .uk for the United Kingdom
+		{hint: "GB", wantAddress: "Springfield", wantRegion: "uk"},
+		{hint: "gb", wantAddress: "Springfield", wantRegion: "uk"},
+		{hint: "Illinois", wantAddress: "Springfield, Illinois"},
+		// Not ISO 3166-1 codes
+		{hint: "UK", wantAddress: "Springfield, UK"},
+	}
+	for _, tt := range tests {
+		g, query := testGoogleGeocoder(t)
+		if _, err := g.GeocodeHinted(context.Background(), "Springfield", tt.hint); err != nil {
+			t.Fatalf("GeocodeHinted() error = %v", err)
+		}
+		if q := query(); q["address"] != tt.wantAddress || q["region"] != tt.wantRegion {
+			t.Errorf("hint %q: requested address %q in region %q, want %q in region %q", tt.hint, q["address"], q["region"], tt.wantAddress, tt.wantRegion)
+		}
+	}
+}
@@ -1,36 +1,34 @@
 package location
 
 import (
-	"context"
-	"regexp"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/gocolly/colly"
 	"github.com/luxas/speakerdeck-api"
 	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
 	log "github.com/sirupsen/logrus"
-	"googlemaps.github.io/maps"
 )
 
-var locationRegexp = regexp.MustCompile(`Location: (.*)`)
-
 var _ scraper.Extension = &LocationExtension{}
 
-// NewLocationExtension creates a new LocationExtension using a Google Maps API Key with access
-// to the Geocoding API.
-func NewLocationExtension(apiKey string) (*LocationExtension, error) {
-	c, err := maps.NewClient(maps.WithAPIKey(apiKey))
-	if err != nil {
-		return nil, err
-	}
-
-	return &LocationExtension{c}, nil
+// NewLocationExtension creates a new LocationExtension resolving the locations of talks using g,
+// e.g. a GoogleGeocoder or a NominatimGeocoder. Time zones not reported by g are resolved offline
+// using DefaultTimezones, unless Timezones is set.
+func NewLocationExtension(g Geocoder) *LocationExtension {
+	return &LocationExtension{g: g}
 }
 
-// LocationExtension implements scraper.Extension, and adds geolocation features to the TalkScraper
-// LocationExtension only works together with speakerdeck.TalkScraper at the moment.
+// LocationExtension implements scraper.Extension, and adds geolocation features to the TalkScraper.
+// Its typed.Extension[speakerdeck.Talk] is returned by Typed.
 type LocationExtension struct {
-	c *maps.Client
+	// Timezones resolves the time zones of the locations whose time zone isn't known by the Geocoder,
+	// e.g. a Gazetteer. If nil, or if it doesn't know the time zone, DefaultTimezones is used
+	Timezones TimezoneResolver
+
+	g Geocoder
 }
 
 // Name returns the LocationExtension name
@@ -38,52 +36,57 @@ func (_ *LocationExtension) Name() string {
 	return "LocationExtension"
 }
 
-// Hook returns the hook for this extension
+// Hook returns the hook for this extension. It matches the talk description as a whole, which is
+// processed after the paragraphs of the description have been parsed by the TalkScraper.
 func (le *LocationExtension) Hook() scraper.Hook {
-	return scraper.Hook{
-		DOMPath: ".deck-description.mb-4 p",
+	return typed.AdaptExtension(le.Typed()).Hook()
+}
+
+// Typed returns the LocationExtension as a typed.Extension[speakerdeck.Talk]
+func (le *LocationExtension) Typed() typed.Extension[speakerdeck.Talk] {
+	return typed.NewExtension(le.Name(), typed.Hook[speakerdeck.Talk]{
+		DOMPath: ".deck-description.mb-4",
 		Handler: le.onDescription,
-	}
+	})
 }
 
-// onDescription processes the location given in the Talk description field, and registers the geocoded
-// response to the Talk object.
-func (le *LocationExtension) onDescription(e *colly.HTMLElement, data interface{}) (*string, error) {
-	// Fail fast, only consider descriptions with the "Location" substring
-	if !strings.Contains(e.Text, "Location") {
+// onDescription processes the location given in the "Location" metadata of the Talk description, and
+// registers the geocoded response to the Talk object. Locations given as coordinates, either as
+// "Location: <lat>,<lng>" or "Location: <name> @ <lat>,<lng>", are not geocoded. The "Location-Hint"
+// metadata biases the geocoder towards a country or region.
+func (le *LocationExtension) onDescription(e *colly.HTMLElement, t *speakerdeck.Talk) (*string, error) {
+	address := t.Metadata.Get("Location")
+	if len(address) == 0 {
 		return nil, nil
 	}
-
-	switch data.(type) {
-	case *speakerdeck.Talk:
-		// noop, allow this
-	default:
-		return nil, nil
+	if len(t.Metadata.Values("Location")) > 1 {
+		log.Warnf("Found more than one location for talk %s! Will only respect the first one.", e.Request.URL)
 	}
 
-	t := data.(*speakerdeck.Talk)
-
-	if strings.Contains(e.Text, "Location: Online") {
+	if address == "Online" {
 		t.Location = &speakerdeck.Location{
 			RequestedAddress: "Online",
 		}
 		return nil, nil
 	}
 
-	locationStr := locationRegexp.FindStringSubmatch(e.Text)
-	if len(locationStr) != 2 {
-		log.Warnf("Couldn't find location for talk %s: %v %q", e.Request.URL, locationStr, e.Text)
-		return nil, nil
-	}
-
 	l := &speakerdeck.Location{
-		RequestedAddress: locationStr[1],
+		RequestedAddress: address,
+		Hint:             t.Metadata.Get("Location-Hint"),
 	}
 
-	r := &maps.GeocodingRequest{
-		Address: l.RequestedAddress,
+	if name, lat, lng, ok := parseExplicitLocation(address); ok {
+		l.ResolvedAddress = name
+		l.Lat = lat
+		l.Lng = lng
+		l.Timezone = le.timezone(lat, lng)
+		l.Confidence = 1
+		log.Infof("Found explicit coordinates for %q: %f %f", l.RequestedAddress, l.Lat, l.Lng)
+		t.Location = l
+		return nil, nil
 	}
-	results, err := le.c.Geocode(context.Background(), r)
+
+	results, err := GeocodeHinted(scraper.Context(e), le.g, l.RequestedAddress, l.Hint)
 	if err != nil {
 		return nil, err
 	}
@@ -93,15 +96,82 @@ func (le *LocationExtension) onDescription(e *colly.HTMLElement, data interface{
 		return nil, nil
 	}
 
-	if len(results) > 1 {
-		log.Warnf("Got more than one result for %q! Will only respect the first one.", l.RequestedAddress)
+	if n := countAmbiguous(results); n > 1 {
+		log.Warnf("Got %d results matching %q about equally well! Will only respect the first one.", n, l.RequestedAddress)
 	}
 
-	l.ResolvedAddress = results[0].FormattedAddress
-	l.Lat = results[0].Geometry.Location.Lat
-	l.Lng = results[0].Geometry.Location.Lng
+	r := results[0]
+	l.ResolvedAddress = r.FormattedAddress
+	l.Lat = r.Lat
+	l.Lng = r.Lng
+	l.City = r.City
+	l.Region = r.Region
+	l.Country = r.Country
+	l.CountryCode = r.CountryCode
+	l.PlaceID = r.PlaceID
+	l.Types = r.Types
+	l.Timezone = r.Timezone
+	// Countries span many time zones, so their time zone can't be told from their coordinates
+	if len(l.Timezone) == 0 && !hasType(l.Types, "country") {
+		l.Timezone = le.timezone(l.Lat, l.Lng)
+	}
+	l.Confidence = r.Confidence
+	for _, c := range results {
+		l.Candidates = append(l.Candidates, speakerdeck.LocationCandidate{
+			ResolvedAddress: c.FormattedAddress,
+			Lat:             c.Lat,
+			Lng:             c.Lng,
+			CountryCode:     c.CountryCode,
+			Confidence:      c.Confidence,
+		})
+	}
 
 	log.Infof("Found geolocation for %q: %f %f", l.RequestedAddress, l.Lat, l.Lng)
 	t.Location = l
 	return nil, nil
 }
+
+// timezone resolves the time zone of the coordinates offline, or returns an empty string if it's not known
+func (le *LocationExtension) timezone(lat, lng float64) string {
+	if le.Timezones != nil {
+		if tz := le.Timezones.Timezone(lat, lng); len(tz) > 0 {
+			return tz
+		}
+	}
+	return DefaultTimezones.Timezone(lat, lng)
+}
+
+// ambiguityMargin is how much lower the confidence of a result may be than the confidence of the best
+// result, for the results to be considered to match the address about equally well
+const ambiguityMargin = 0.1
+
+// countAmbiguous returns how many of the results match about as well as the first one
+func countAmbiguous(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if results[0].Confidence-r.Confidence <= ambiguityMargin {
+			n++
+		}
+	}
+	return n
+}
+
+// parseExplicitLocation parses locations given as "<lat>,<lng>" or "<name> @ <lat>,<lng>", and returns
+// the name (if any) and the coordinates. false is returned if location isn't given in either form.
+func parseExplicitLocation(location string) (name string, lat, lng float64, ok bool) {
+	coordinates := location
+	if i := strings.LastIndex(location, "@"); i >= 0 {
+		name, coordinates = strings.TrimSpace(location[:i]), location[i+1:]
+	}
+	latStr, lngStr, found := strings.Cut(coordinates, ",")
+	if !found {
+		return "", 0, 0, false
+	}
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	lng, lngErr := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	// The negated comparisons also reject NaN
+	if latErr != nil || lngErr != nil || !(math.Abs(lat) <= 90) || !(math.Abs(lng) <= 180) {
+		return "", 0, 0, false
+	}
+	return name, lat, lng, true
+}
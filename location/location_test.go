@@ -0,0 +1,70 @@
+package location
+
+import (
+	"testing"
+)
+
+func TestParseExplicitLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		wantName string
+		wantLat  float64
+		wantLng  float64
+		wantOK   bool
+	}{
+		{location: "60.1699,24.9384", wantLat: 60.1699, wantLng: 24.9384, wantOK: true},
+		{location: " -33.8568 , 151.2153 ", wantLat: -33.8568, wantLng: 151.2153, wantOK: true},
+		{location: "Bella Center @ 55.6377,12.5785", wantName: "Bella Center", wantLat: 55.6377, wantLng: 12.5785, wantOK: true},
+		{location: "@me @ 1,2", wantName: "@me", wantLat: 1, wantLng: 2, wantOK: true},
+		{location: "Barcelona, Spain"},
+		{location: "Online"},
+		{location: "91,0"},
+		{location: "0,-181"},
+		{location: "NaN,0"},
+		{location: "Bella Center @ Copenhagen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			name, lat, lng, ok := parseExplicitLocation(tt.location)
+			if ok != tt.wantOK || name != tt.wantName || lat != tt.wantLat || lng != tt.wantLng {
+				t.Errorf("parseExplicitLocation() = %q, %v, %v, %v, want %q, %v, %v, %v", name, lat, lng, ok, tt.wantName, tt.wantLat, tt.wantLng, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHintedAddress(t *testing.T) {
+	tests := []struct {
+		address, hint, want string
+	}{
+		{address: "Springfield", hint: "", want: "Springfield"},
+		{address: "Springfield", hint: "Missouri", want: "Springfield, Missouri"},
+		{address: "Springfield, Missouri", hint: "missouri", want: "Springfield, Missouri"},
+		{address: "Paris", hint: "TX", want: "Paris, TX"},
+		{address: "Texas City", hint: "TX", want: "Texas City, TX"},
+	}
+	for _, tt := range tests {
+		if got := hintedAddress(tt.address, tt.hint); got != tt.want {
+			t.Errorf("hintedAddress(%q, %q) = %q, want %q", tt.address, tt.hint, got, tt.want)
+		}
+	}
+}
+
+func TestIsCountryCode(t *testing.T) {
+	tests := map[string]bool{
+		"FI":      true,
+		"fi":      true,
+		"CA":      true,
+		"TX":      false,
+		"MO":      true,
+		"ZZ":      false,
+		"FIN":     false,
+		"Finland": false,
+		"":        false,
+	}
+	for hint, want := range tests {
+		if got := isCountryCode(hint); got != want {
+			t.Errorf("isCountryCode(%q) = %v, want %v", hint, got, want)
+		}
+	}
+}
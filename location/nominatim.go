@@ -0,0 +1,184 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultNominatimEndpoint is the search endpoint of the public OpenStreetMap Nominatim instance. Note its
+// usage policy, which e.g. allows at most one request per second: https://operations.osmfoundation.org/policies/nominatim/
+const DefaultNominatimEndpoint = "https://nominatim.openstreetmap.org/search"
+
+// publicNominatimLimiter limits the requests to DefaultNominatimEndpoint to one per second, as required
+// by its usage policy. It's shared by all NominatimGeocoders without a Limiter, as the limit applies to
+// the whole application
+var publicNominatimLimiter = rate.NewLimiter(1, 1)
+
+// defaultNominatimUserAgent identifies the API to Nominatim, as required by its usage policy
+const defaultNominatimUserAgent = "speakerdeck-api (https://github.com/luxas/speakerdeck-api)"
+
+var _ HintedGeocoder = &NominatimGeocoder{}
+
+// NewNominatimGeocoder creates a new NominatimGeocoder using the given search endpoint of a Nominatim-compatible
+// API. If endpoint is empty, DefaultNominatimEndpoint is used.
+func NewNominatimGeocoder(endpoint string) *NominatimGeocoder {
+	if len(endpoint) == 0 {
+		endpoint = DefaultNominatimEndpoint
+	}
+	return &NominatimGeocoder{
+		Endpoint: endpoint,
+	}
+}
+
+// NominatimGeocoder implements Geocoder using an OpenStreetMap Nominatim-compatible search API
+type NominatimGeocoder struct {
+	// Endpoint is the URL of the search API, e.g. DefaultNominatimEndpoint
+	Endpoint string
+
+	// UserAgent is the User-Agent header sent with all requests. If empty, a User-Agent identifying
+	// this project is used, as Nominatim requires one
+	UserAgent string
+
+	// Limit is the maximum amount of results to ask for. If zero, the API default is used
+	Limit int
+
+	// Client is the HTTP client used for the requests. If nil, http.DefaultClient is used
+	Client *http.Client
+
+	// Limiter limits the rate of requests. If nil, requests to DefaultNominatimEndpoint are limited to
+	// one per second, as required by its usage policy, while requests to other endpoints aren't limited
+	Limiter *rate.Limiter
+}
+
+// limiter returns the rate limiter for the requests, or nil if the rate isn't limited
+func (g *NominatimGeocoder) limiter() *rate.Limiter {
+	if g.Limiter == nil && g.Endpoint == DefaultNominatimEndpoint {
+		return publicNominatimLimiter
+	}
+	return g.Limiter
+}
+
+// nominatimPlace is a place in the jsonv2 output format of Nominatim
+type nominatimPlace struct {
+	DisplayName string `json:"display_name"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	OSMType     string `json:"osm_type"`
+	OSMID       int64  `json:"osm_id"`
+	Category    string `json:"category"`
+	Type        string `json:"type"`
+	Address     struct {
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		State       string `json:"state"`
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// city returns the city, town or village of the place, whichever is known
+func (p *nominatimPlace) city() string {
+	for _, city := range []string{p.Address.City, p.Address.Town, p.Address.Village} {
+		if len(city) > 0 {
+			return city
+		}
+	}
+	return ""
+}
+
+// Geocode implements Geocoder
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) ([]Result, error) {
+	return g.GeocodeHinted(ctx, address, "")
+}
+
+// GeocodeHinted implements HintedGeocoder. Country codes restrict the results to that country, while
+// other hints are made part of the address.
+func (g *NominatimGeocoder) GeocodeHinted(ctx context.Context, address, hint string) ([]Result, error) {
+	q := url.Values{}
+	if isCountryCode(hint) {
+		q.Set("q", address)
+		q.Set("countrycodes", strings.ToLower(hint))
+	} else {
+		q.Set("q", hintedAddress(address, hint))
+	}
+	q.Set("format", "jsonv2")
+	q.Set("addressdetails", "1")
+	if g.Limit > 0 {
+		q.Set("limit", strconv.Itoa(g.Limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.Endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	userAgent := g.UserAgent
+	if len(userAgent) == 0 {
+		userAgent = defaultNominatimUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if l := g.limiter(); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim search for %q failed: %s", address, resp.Status)
+	}
+
+	places := []nominatimPlace{}
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("could not decode nominatim response for %q: %w", address, err)
+	}
+
+	results := make([]Result, 0, len(places))
+	for i := range places {
+		p := &places[i]
+		lat, err := strconv.ParseFloat(p.Lat, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude %q in nominatim response for %q: %w", p.Lat, address, err)
+		}
+		lng, err := strconv.ParseFloat(p.Lon, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude %q in nominatim response for %q: %w", p.Lon, address, err)
+		}
+		r := Result{
+			FormattedAddress: p.DisplayName,
+			Lat:              lat,
+			Lng:              lng,
+			City:             p.city(),
+			Region:           p.Address.State,
+			Country:          p.Address.Country,
+			CountryCode:      strings.ToUpper(p.Address.CountryCode),
+			Confidence:       1,
+		}
+		// The place_id is specific to the Nominatim instance, while OpenStreetMap IDs are stable
+		if len(p.OSMType) > 0 {
+			r.PlaceID = fmt.Sprintf("osm:%s/%d", p.OSMType, p.OSMID)
+		}
+		for _, t := range []string{p.Category, p.Type} {
+			if len(t) > 0 {
+				r.Types = append(r.Types, t)
+			}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
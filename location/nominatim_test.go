@@ -0,0 +1,185 @@
+package location
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const nominatimResponse = `[
+  {
+    "place_id": 123456,
+    "osm_type": "way",
+    "osm_id": 24981342,
+    "lat": "41.3543",
+    "lon": "2.1277",
+    "category": "amenity",
+    "type": "exhibition_centre",
+    "display_name": "Fira Gran Via, Avinguda de Joan Carles I, L'Hospitalet de Llobregat, Barcelonès, Barcelona, Catalunya, 08908, España",
+    "address": {
+      "town": "L'Hospitalet de Llobregat",
+      "state": "Catalunya",
+      "country": "España",
+      "country_code": "es"
+    }
+  },
+  {
+    "place_id": 654321,
+    "osm_type": "relation",
+    "osm_id": 347950,
+    "lat": "41.3828939",
+    "lon": "2.1774322",
+    "category": "boundary",
+    "type": "administrative",
+    "display_name": "Barcelona, Barcelonès, Barcelona, Catalunya, España",
+    "address": {
+      "city": "Barcelona",
+      "village": "Not the city",
+      "state": "Catalunya",
+      "country": "España",
+      "country_code": "es"
+    }
+  }
+]`
+
+func TestNominatimGeocoder(t *testing.T) {
+	var query url.Values
+	var userAgent string
+	response, status := nominatimResponse, http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, userAgent = r.URL.Query(), r.UserAgent()
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	g := NewNominatimGeocoder(srv.URL)
+	results, err := g.GeocodeHinted(context.Background(), "Fira Gran Via, Barcelona", "ES")
+	if err != nil {
+		t.Fatalf("GeocodeHinted() error = %v", err)
+	}
+	want := []Result{
+		{
+			FormattedAddress: "Fira Gran Via, Avinguda de Joan Carles I, L'Hospitalet de Llobregat, Barcelonès, Barcelona, Catalunya, 08908, España",
+			Lat:              41.3543,
+			Lng:              2.1277,
+			City:             "L'Hospitalet de Llobregat",
+			Region:           "Catalunya",
+			Country:          "España",
+			CountryCode:      "ES",
+			PlaceID:          "osm:way/24981342",
+			Types:            []string{"amenity", "exhibition_centre"},
+			Confidence:       1,
+		},
+		{
+			FormattedAddress: "Barcelona, Barcelonès, Barcelona, Catalunya, España",
+			Lat:              41.3828939,
+			Lng:              2.1774322,
+			City:             "Barcelona",
+			Region:           "Catalunya",
+			Country:          "España",
+			CountryCode:      "ES",
+			PlaceID:          "osm:relation/347950",
+			Types:            []string{"boundary", "administrative"},
+			Confidence:       1,
+		},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("GeocodeHinted() = %+v, want %+v", results, want)
+	}
+	wantQuery := url.Values{
+		"q":              {"Fira Gran Via, Barcelona"},
+		"countrycodes":   {"es"},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+	}
+	if !reflect.DeepEqual(query, wantQuery) {
+		t.Errorf("query = %v, want %v", query, wantQuery)
+	}
+	if userAgent != defaultNominatimUserAgent {
+		t.Errorf("User-Agent = %q, want %q", userAgent, defaultNominatimUserAgent)
+	}
+
+	// Other hints than country codes are made part of the address
+	g.Limit, g.UserAgent = 3, "my-app"
+	if _, err := g.GeocodeHinted(context.Background(), "Fira Gran Via", "Barcelona"); err != nil {
+		t.Fatalf("GeocodeHinted() error = %v", err)
+	}
+	wantQuery = url.Values{
+		"q":              {"Fira Gran Via, Barcelona"},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"limit":          {"3"},
+	}
+	if !reflect.DeepEqual(query, wantQuery) || userAgent != "my-app" {
+		t.Errorf("query = %v with User-Agent %q, want %v with my-app", query, userAgent, wantQuery)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		response string
+		status   int
+	}{
+		{name: "error status", response: `{"error": "too many requests"}`, status: http.StatusTooManyRequests},
+		{name: "invalid JSON", response: `[{`, status: http.StatusOK},
+		{name: "invalid latitude", response: `[{"lat": "north", "lon": "2.1"}]`, status: http.StatusOK},
+	} {
+		response, status = tt.response, tt.status
+		if _, err := g.Geocode(context.Background(), "Fira Gran Via"); err == nil {
+			t.Errorf("%s: Geocode() succeeded, want error", tt.name)
+		}
+	}
+}
+
+// roundTripperFunc implements http.RoundTripper using a function
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNominatimLimiter(t *testing.T) {
+	custom := rate.NewLimiter(10, 2)
+	tests := []struct {
+		name string
+		g    *NominatimGeocoder
+		want *rate.Limiter
+	}{
+		{name: "public instance", g: NewNominatimGeocoder(""), want: publicNominatimLimiter},
+		{name: "own instance", g: NewNominatimGeocoder("http://localhost:8080/search"), want: nil},
+		{name: "explicit Limiter", g: &NominatimGeocoder{Endpoint: DefaultNominatimEndpoint, Limiter: custom}, want: custom},
+	}
+	for _, tt := range tests {
+		if got := tt.g.limiter(); got != tt.want {
+			t.Errorf("%s: limiter() = %p, want %p", tt.name, got, tt.want)
+		}
+	}
+	if publicNominatimLimiter.Limit() != 1 || publicNominatimLimiter.Burst() != 1 {
+		t.Errorf("public limiter allows %v requests per second with burst %d, want 1 with burst 1",
+			publicNominatimLimiter.Limit(), publicNominatimLimiter.Burst())
+	}
+
+	// Requests to the public instance, served here by a test server, are made one per second
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+	g := NewNominatimGeocoder("")
+	g.Client = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.URL.Scheme, req.URL.Host = "http", srv.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := g.Geocode(context.Background(), "Bella Center"); err != nil {
+			t.Fatalf("Geocode() error = %v", err)
+		}
+	}
+	if took := time.Since(start); took < 900*time.Millisecond {
+		t.Errorf("two requests to the public instance took %v, want at least a second", took)
+	}
+}
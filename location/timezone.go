@@ -0,0 +1,127 @@
+package location
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxTimezoneCells is how many grid cells (degrees) away from the coordinates the Gazetteer looks for
+// places with a known time zone. Places further away than this aren't considered to tell the time zone
+const maxTimezoneCells = 2
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// maxDefaultTimezoneKm is how far from the coordinates DefaultTimezones looks for a city. Coordinates
+// further away than this from all of its cities, e.g. at sea, don't get a time zone
+const maxDefaultTimezoneKm = 800.0
+
+// TimezoneResolver resolves the IANA time zones of coordinates offline, e.g. the Gazetteer by the nearest
+// known place.
+type TimezoneResolver interface {
+	// Timezone returns the IANA time zone at the coordinates, e.g. "Europe/Helsinki", or an empty
+	// string if it's not known
+	Timezone(lat, lng float64) string
+}
+
+// TimezoneResolverFunc implements TimezoneResolver using a function
+type TimezoneResolverFunc func(lat, lng float64) string
+
+// Timezone implements TimezoneResolver
+func (f TimezoneResolverFunc) Timezone(lat, lng float64) string {
+	return f(lat, lng)
+}
+
+// DefaultTimezones resolves time zones offline from a built-in list of a few hundred major cities
+// around the world, by returning the time zone of the nearest one. It's used by the LocationExtension
+// for locations whose time zone is known by neither the Geocoder nor LocationExtension.Timezones.
+// Close to the borders of time zones, the nearest major city may be in the neighbouring time zone;
+// use a Gazetteer loaded with the GeoNames datasets for more accurate results.
+var DefaultTimezones TimezoneResolver = TimezoneResolverFunc(defaultTimezone)
+
+// defaultTimezoneCity is a city of defaultTimezoneData
+type defaultTimezoneCity struct {
+	lat, lng float64
+	timezone string
+}
+
+var (
+	defaultTimezoneCities     []defaultTimezoneCity
+	defaultTimezoneCitiesOnce sync.Once
+)
+
+func defaultTimezone(lat, lng float64) string {
+	defaultTimezoneCitiesOnce.Do(func() {
+		for _, line := range strings.Split(strings.TrimSpace(defaultTimezoneData), "\n") {
+			fields := strings.Split(line, "\t")
+			cLat, latErr := strconv.ParseFloat(fields[1], 64)
+			cLng, lngErr := strconv.ParseFloat(fields[2], 64)
+			if latErr != nil || lngErr != nil {
+				panic("invalid coordinates in the default time zone data: " + line)
+			}
+			defaultTimezoneCities = append(defaultTimezoneCities, defaultTimezoneCity{cLat, cLng, fields[3]})
+		}
+	})
+
+	timezone, nearest := "", maxDefaultTimezoneKm
+	for _, c := range defaultTimezoneCities {
+		if d := distanceKm(lat, lng, c.lat, c.lng); d <= nearest {
+			timezone, nearest = c.timezone, d
+		}
+	}
+	return timezone
+}
+
+var _ TimezoneResolver = &Gazetteer{}
+
+// Timezone implements TimezoneResolver, by returning the time zone of the nearest place in the Gazetteer
+// with a known time zone, e.g. from the GeoNames datasets. An empty string is returned if there are no
+// such places within a couple of hundred kilometers.
+func (g *Gazetteer) Timezone(lat, lng float64) string {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	return g.timezone(lat, lng)
+}
+
+func (g *Gazetteer) timezone(lat, lng float64) string {
+	center := cellOf(lat, lng)
+	timezone, nearest := "", math.Inf(1)
+	for dLat := -maxTimezoneCells; dLat <= maxTimezoneCells; dLat++ {
+		for dLng := -maxTimezoneCells; dLng <= maxTimezoneCells; dLng++ {
+			cell := gridCell{lat: center.lat + dLat, lng: wrapLng(center.lng + dLng)}
+			for _, i := range g.timezones[cell] {
+				e := g.entries[i]
+				if d := distanceKm(lat, lng, e.Lat, e.Lng); d < nearest {
+					timezone, nearest = e.Timezone, d
+				}
+			}
+		}
+	}
+	return timezone
+}
+
+// gridCell is a cell of one by one degrees, for finding nearby places
+type gridCell struct {
+	lat, lng int
+}
+
+func cellOf(lat, lng float64) gridCell {
+	return gridCell{lat: int(math.Floor(lat)), lng: wrapLng(int(math.Floor(lng)))}
+}
+
+// wrapLng wraps the longitude of a grid cell to [-180, 180), so cells next to the antimeridian are neighbours
+func wrapLng(lng int) int {
+	return ((lng+180)%360+360)%360 - 180
+}
+
+// distanceKm returns the great-circle distance between two coordinates using the haversine formula
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLng := (lng2 - lng1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,107 @@
+package location
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+func TestDefaultTimezones(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+		want     string
+	}{
+		{name: "Fira Gran Via, Barcelona", lat: 41.354, lng: 2.128, want: "Europe/Madrid"},
+		{name: "Bella Center, Copenhagen", lat: 55.6377, lng: 12.5785, want: "Europe/Copenhagen"},
+		{name: "Espoo, near Helsinki", lat: 60.2055, lng: 24.6559, want: "Europe/Helsinki"},
+		{name: "Moscone Center, San Francisco", lat: 37.784, lng: -122.401, want: "America/Los_Angeles"},
+		{name: "Raleigh Convention Center", lat: 35.772, lng: -78.639, want: "America/New_York"},
+		{name: "Shibuya, Tokyo", lat: 35.658, lng: 139.7016, want: "Asia/Tokyo"},
+		{name: "Kochi, far from the listed cities", lat: 9.9312, lng: 76.2673, want: "Asia/Kolkata"},
+		{name: "across the antimeridian from Suva", lat: -17.7, lng: -179.9, want: "Pacific/Fiji"},
+		{name: "middle of the Pacific", lat: 0, lng: -140, want: ""},
+		{name: "middle of the Atlantic", lat: 30, lng: -40, want: ""},
+	}
+	for _, tt := range tests {
+		if got := DefaultTimezones.Timezone(tt.lat, tt.lng); got != tt.want {
+			t.Errorf("%s: Timezone(%v, %v) = %q, want %q", tt.name, tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultTimezoneData(t *testing.T) {
+	for _, line := range strings.Split(strings.TrimSpace(defaultTimezoneData), "\n") {
+		if fields := strings.Split(line, "\t"); len(fields) != 4 || !strings.Contains(fields[3], "/") {
+			t.Errorf("invalid line %q, want name, latitude, longitude and time zone", line)
+		}
+	}
+}
+
+func TestLocationExtensionTimezone(t *testing.T) {
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+
+	fira := Result{FormattedAddress: "Av. Joan Carles I, 64, Barcelona, Spain", Lat: 41.354, Lng: 2.128, Confidence: 1}
+	tests := []struct {
+		name      string
+		result    Result
+		timezones TimezoneResolver
+		want      string
+	}{
+		{
+			name:   "resolved offline by default",
+			result: fira,
+			want:   "Europe/Madrid",
+		},
+		{
+			name: "reported by the geocoder",
+			result: Result{
+				FormattedAddress: fira.FormattedAddress, Lat: fira.Lat, Lng: fira.Lng, Confidence: 1,
+				Timezone: "Europe/Andorra",
+			},
+			want: "Europe/Andorra",
+		},
+		{
+			name:      "resolved by Timezones",
+			result:    fira,
+			timezones: TimezoneResolverFunc(func(lat, lng float64) string { return "Africa/Ceuta" }),
+			want:      "Africa/Ceuta",
+		},
+		{
+			name:      "DefaultTimezones is used when Timezones doesn't know",
+			result:    fira,
+			timezones: TimezoneResolverFunc(func(lat, lng float64) string { return "" }),
+			want:      "Europe/Madrid",
+		},
+		{
+			name:   "countries span many time zones",
+			result: Result{FormattedAddress: "Spain", Lat: 40, Lng: -4, Types: []string{"country"}, Confidence: 1},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewFakeGeocoder(map[string][]Result{
+				"Fira Gran Via, Av. Joan Carles I, Barcelona, Spain": {tt.result},
+			})
+			le := NewLocationExtension(g)
+			le.Timezones = tt.timezones
+
+			talks, err := s.NewClient().ScrapeTalks("luxas", "getting-started-in-the-kubernetes-community", &scraper.ScrapeOptions{
+				Extensions: []scraper.Extension{le},
+			})
+			if err != nil {
+				t.Fatalf("ScrapeTalks() error = %v", err)
+			}
+			if len(talks) != 1 || talks[0].Location == nil {
+				t.Fatalf("ScrapeTalks() = %v, want one talk with a location", talks)
+			}
+			if got := talks[0].Location.Timezone; got != tt.want {
+				t.Errorf("time zone = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
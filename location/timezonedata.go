@@ -0,0 +1,300 @@
+package location
+
+// defaultTimezoneData lists major cities and the IANA time zones they are in, as tab-separated name,
+// latitude, longitude and time zone columns. It's used by DefaultTimezones, so that the time zones of
+// most talk locations can be resolved offline without loading any GeoNames files. Large countries
+// have several cities, so that the nearest city is in the right time zone for most places.
+const defaultTimezoneData = `Helsinki	60.17	24.94	Europe/Helsinki
+Tampere	61.50	23.76	Europe/Helsinki
+Oulu	65.01	25.47	Europe/Helsinki
+Stockholm	59.33	18.07	Europe/Stockholm
+Gothenburg	57.71	11.97	Europe/Stockholm
+Umeå	63.83	20.26	Europe/Stockholm
+Oslo	59.91	10.75	Europe/Oslo
+Bergen	60.39	5.32	Europe/Oslo
+Trondheim	63.43	10.40	Europe/Oslo
+Tromsø	69.65	18.96	Europe/Oslo
+Copenhagen	55.68	12.57	Europe/Copenhagen
+Aarhus	56.16	10.20	Europe/Copenhagen
+Reykjavík	64.15	-21.94	Atlantic/Reykjavik
+Tallinn	59.44	24.75	Europe/Tallinn
+Riga	56.95	24.11	Europe/Riga
+Vilnius	54.69	25.28	Europe/Vilnius
+Warsaw	52.23	21.01	Europe/Warsaw
+Kraków	50.06	19.94	Europe/Warsaw
+Gdańsk	54.35	18.65	Europe/Warsaw
+Wrocław	51.11	17.04	Europe/Warsaw
+Berlin	52.52	13.40	Europe/Berlin
+Hamburg	53.55	9.99	Europe/Berlin
+Munich	48.14	11.58	Europe/Berlin
+Cologne	50.94	6.96	Europe/Berlin
+Frankfurt	50.11	8.68	Europe/Berlin
+Amsterdam	52.37	4.90	Europe/Amsterdam
+Brussels	50.85	4.35	Europe/Brussels
+Luxembourg	49.61	6.13	Europe/Luxembourg
+Paris	48.86	2.35	Europe/Paris
+Lyon	45.76	4.84	Europe/Paris
+Marseille	43.30	5.37	Europe/Paris
+Bordeaux	44.84	-0.58	Europe/Paris
+Nantes	47.22	-1.55	Europe/Paris
+London	51.51	-0.13	Europe/London
+Manchester	53.48	-2.24	Europe/London
+Edinburgh	55.95	-3.19	Europe/London
+Belfast	54.60	-5.93	Europe/London
+Dublin	53.35	-6.26	Europe/Dublin
+Cork	51.90	-8.47	Europe/Dublin
+Madrid	40.42	-3.70	Europe/Madrid
+Barcelona	41.39	2.17	Europe/Madrid
+Seville	37.39	-5.98	Europe/Madrid
+Bilbao	43.26	-2.93	Europe/Madrid
+Lisbon	38.72	-9.14	Europe/Lisbon
+Porto	41.15	-8.61	Europe/Lisbon
+Zürich	47.38	8.54	Europe/Zurich
+Geneva	46.20	6.14	Europe/Zurich
+Vienna	48.21	16.37	Europe/Vienna
+Prague	50.08	14.44	Europe/Prague
+Bratislava	48.15	17.11	Europe/Bratislava
+Budapest	47.50	19.04	Europe/Budapest
+Ljubljana	46.06	14.51	Europe/Ljubljana
+Zagreb	45.81	15.98	Europe/Zagreb
+Belgrade	44.79	20.45	Europe/Belgrade
+Sarajevo	43.86	18.41	Europe/Sarajevo
+Podgorica	42.44	19.26	Europe/Podgorica
+Skopje	42.00	21.43	Europe/Skopje
+Tirana	41.33	19.82	Europe/Tirane
+Rome	41.90	12.50	Europe/Rome
+Milan	45.46	9.19	Europe/Rome
+Naples	40.85	14.27	Europe/Rome
+Palermo	38.12	13.36	Europe/Rome
+Valletta	35.90	14.51	Europe/Malta
+Athens	37.98	23.73	Europe/Athens
+Thessaloniki	40.64	22.94	Europe/Athens
+Sofia	42.70	23.32	Europe/Sofia
+Bucharest	44.43	26.10	Europe/Bucharest
+Cluj-Napoca	46.77	23.60	Europe/Bucharest
+Chișinău	47.01	28.86	Europe/Chisinau
+Kyiv	50.45	30.52	Europe/Kyiv
+Lviv	49.84	24.03	Europe/Kyiv
+Odesa	46.48	30.72	Europe/Kyiv
+Kharkiv	49.99	36.23	Europe/Kyiv
+Minsk	53.90	27.57	Europe/Minsk
+Kaliningrad	54.71	20.51	Europe/Kaliningrad
+Moscow	55.76	37.62	Europe/Moscow
+Saint Petersburg	59.93	30.34	Europe/Moscow
+Kazan	55.79	49.12	Europe/Moscow
+Samara	53.20	50.15	Europe/Samara
+Yekaterinburg	56.84	60.61	Asia/Yekaterinburg
+Omsk	54.99	73.37	Asia/Omsk
+Novosibirsk	55.03	82.92	Asia/Novosibirsk
+Krasnoyarsk	56.01	92.87	Asia/Krasnoyarsk
+Irkutsk	52.29	104.28	Asia/Irkutsk
+Yakutsk	62.03	129.73	Asia/Yakutsk
+Vladivostok	43.12	131.89	Asia/Vladivostok
+Istanbul	41.01	28.98	Europe/Istanbul
+Ankara	39.93	32.86	Europe/Istanbul
+İzmir	38.42	27.14	Europe/Istanbul
+Nicosia	35.17	33.36	Asia/Nicosia
+Tbilisi	41.72	44.79	Asia/Tbilisi
+Yerevan	40.18	44.51	Asia/Yerevan
+Baku	40.41	49.87	Asia/Baku
+Tel Aviv	32.09	34.78	Asia/Jerusalem
+Amman	31.95	35.93	Asia/Amman
+Beirut	33.89	35.50	Asia/Beirut
+Damascus	33.51	36.29	Asia/Damascus
+Baghdad	33.31	44.36	Asia/Baghdad
+Riyadh	24.71	46.68	Asia/Riyadh
+Jeddah	21.49	39.19	Asia/Riyadh
+Kuwait City	29.38	47.99	Asia/Kuwait
+Doha	25.29	51.53	Asia/Qatar
+Manama	26.23	50.59	Asia/Bahrain
+Dubai	25.20	55.27	Asia/Dubai
+Muscat	23.59	58.41	Asia/Muscat
+Tehran	35.69	51.39	Asia/Tehran
+Kabul	34.56	69.21	Asia/Kabul
+Karachi	24.86	67.01	Asia/Karachi
+Lahore	31.55	74.34	Asia/Karachi
+Tashkent	41.30	69.24	Asia/Tashkent
+Almaty	43.24	76.89	Asia/Almaty
+Bishkek	42.87	74.59	Asia/Bishkek
+Delhi	28.61	77.21	Asia/Kolkata
+Mumbai	19.08	72.88	Asia/Kolkata
+Pune	18.52	73.86	Asia/Kolkata
+Bengaluru	12.97	77.59	Asia/Kolkata
+Chennai	13.08	80.27	Asia/Kolkata
+Hyderabad	17.39	78.49	Asia/Kolkata
+Kolkata	22.57	88.36	Asia/Kolkata
+Colombo	6.93	79.86	Asia/Colombo
+Kathmandu	27.72	85.32	Asia/Kathmandu
+Dhaka	23.81	90.41	Asia/Dhaka
+Yangon	16.87	96.20	Asia/Yangon
+Bangkok	13.76	100.50	Asia/Bangkok
+Chiang Mai	18.79	98.98	Asia/Bangkok
+Hanoi	21.03	105.85	Asia/Ho_Chi_Minh
+Ho Chi Minh City	10.82	106.63	Asia/Ho_Chi_Minh
+Phnom Penh	11.56	104.92	Asia/Phnom_Penh
+Kuala Lumpur	3.14	101.69	Asia/Kuala_Lumpur
+Singapore	1.35	103.82	Asia/Singapore
+Jakarta	-6.21	106.85	Asia/Jakarta
+Surabaya	-7.25	112.75	Asia/Jakarta
+Denpasar	-8.65	115.22	Asia/Makassar
+Makassar	-5.15	119.43	Asia/Makassar
+Jayapura	-2.53	140.72	Asia/Jayapura
+Manila	14.60	120.98	Asia/Manila
+Cebu	10.32	123.89	Asia/Manila
+Hong Kong	22.32	114.17	Asia/Hong_Kong
+Taipei	25.03	121.57	Asia/Taipei
+Shanghai	31.23	121.47	Asia/Shanghai
+Beijing	39.90	116.41	Asia/Shanghai
+Shenzhen	22.54	114.06	Asia/Shanghai
+Chengdu	30.57	104.07	Asia/Shanghai
+Wuhan	30.59	114.31	Asia/Shanghai
+Xi'an	34.34	108.94	Asia/Shanghai
+Ürümqi	43.83	87.62	Asia/Urumqi
+Ulaanbaatar	47.89	106.91	Asia/Ulaanbaatar
+Seoul	37.57	126.98	Asia/Seoul
+Busan	35.18	129.08	Asia/Seoul
+Tokyo	35.68	139.69	Asia/Tokyo
+Osaka	34.69	135.50	Asia/Tokyo
+Fukuoka	33.59	130.40	Asia/Tokyo
+Sapporo	43.06	141.35	Asia/Tokyo
+Naha	26.21	127.68	Asia/Tokyo
+Sydney	-33.87	151.21	Australia/Sydney
+Canberra	-35.28	149.13	Australia/Sydney
+Melbourne	-37.81	144.96	Australia/Melbourne
+Brisbane	-27.47	153.03	Australia/Brisbane
+Cairns	-16.92	145.77	Australia/Brisbane
+Adelaide	-34.93	138.60	Australia/Adelaide
+Darwin	-12.46	130.84	Australia/Darwin
+Perth	-31.95	115.86	Australia/Perth
+Hobart	-42.88	147.33	Australia/Hobart
+Auckland	-36.85	174.76	Pacific/Auckland
+Wellington	-41.29	174.78	Pacific/Auckland
+Christchurch	-43.53	172.64	Pacific/Auckland
+Suva	-18.14	178.44	Pacific/Fiji
+Port Moresby	-9.44	147.18	Pacific/Port_Moresby
+Nouméa	-22.28	166.46	Pacific/Noumea
+Honolulu	21.31	-157.86	Pacific/Honolulu
+Cairo	30.04	31.24	Africa/Cairo
+Alexandria	31.20	29.92	Africa/Cairo
+Casablanca	33.57	-7.59	Africa/Casablanca
+Rabat	34.02	-6.84	Africa/Casablanca
+Algiers	36.75	3.06	Africa/Algiers
+Tunis	36.81	10.18	Africa/Tunis
+Tripoli	32.89	13.19	Africa/Tripoli
+Khartoum	15.50	32.56	Africa/Khartoum
+Addis Ababa	9.03	38.74	Africa/Addis_Ababa
+Nairobi	-1.29	36.82	Africa/Nairobi
+Kampala	0.35	32.58	Africa/Kampala
+Kigali	-1.95	30.06	Africa/Kigali
+Dar es Salaam	-6.79	39.21	Africa/Dar_es_Salaam
+Lagos	6.52	3.38	Africa/Lagos
+Abuja	9.08	7.40	Africa/Lagos
+Accra	5.60	-0.19	Africa/Accra
+Abidjan	5.36	-4.01	Africa/Abidjan
+Dakar	14.72	-17.47	Africa/Dakar
+Kinshasa	-4.44	15.27	Africa/Kinshasa
+Luanda	-8.84	13.23	Africa/Luanda
+Lusaka	-15.39	28.32	Africa/Lusaka
+Harare	-17.83	31.05	Africa/Harare
+Maputo	-25.97	32.57	Africa/Maputo
+Johannesburg	-26.20	28.05	Africa/Johannesburg
+Cape Town	-33.92	18.42	Africa/Johannesburg
+Durban	-29.86	31.02	Africa/Johannesburg
+Windhoek	-22.56	17.08	Africa/Windhoek
+Antananarivo	-18.88	47.51	Indian/Antananarivo
+Port Louis	-20.16	57.50	Indian/Mauritius
+New York	40.71	-74.01	America/New_York
+Boston	42.36	-71.06	America/New_York
+Philadelphia	39.95	-75.17	America/New_York
+Washington	38.91	-77.04	America/New_York
+Pittsburgh	40.44	-80.00	America/New_York
+Columbus	39.96	-83.00	America/New_York
+Raleigh	35.78	-78.64	America/New_York
+Charlotte	35.23	-80.84	America/New_York
+Atlanta	33.75	-84.39	America/New_York
+Miami	25.76	-80.19	America/New_York
+Detroit	42.33	-83.05	America/Detroit
+Indianapolis	39.77	-86.16	America/Indiana/Indianapolis
+Louisville	38.25	-85.76	America/Kentucky/Louisville
+Chicago	41.88	-87.63	America/Chicago
+Milwaukee	43.04	-87.91	America/Chicago
+Minneapolis	44.98	-93.27	America/Chicago
+St. Louis	38.63	-90.20	America/Chicago
+Kansas City	39.10	-94.58	America/Chicago
+Omaha	41.26	-95.93	America/Chicago
+Nashville	36.16	-86.78	America/Chicago
+Memphis	35.15	-90.05	America/Chicago
+New Orleans	29.95	-90.07	America/Chicago
+Houston	29.76	-95.37	America/Chicago
+Dallas	32.78	-96.80	America/Chicago
+Austin	30.27	-97.74	America/Chicago
+San Antonio	29.42	-98.49	America/Chicago
+Oklahoma City	35.47	-97.52	America/Chicago
+Denver	39.74	-104.99	America/Denver
+Salt Lake City	40.76	-111.89	America/Denver
+Albuquerque	35.08	-106.65	America/Denver
+Boise	43.62	-116.20	America/Boise
+Phoenix	33.45	-112.07	America/Phoenix
+Tucson	32.22	-110.97	America/Phoenix
+Las Vegas	36.17	-115.14	America/Los_Angeles
+Los Angeles	34.05	-118.24	America/Los_Angeles
+San Diego	32.72	-117.16	America/Los_Angeles
+San Francisco	37.77	-122.42	America/Los_Angeles
+San Jose	37.34	-121.89	America/Los_Angeles
+Sacramento	38.58	-121.49	America/Los_Angeles
+Portland	45.52	-122.68	America/Los_Angeles
+Seattle	47.61	-122.33	America/Los_Angeles
+Anchorage	61.22	-149.90	America/Anchorage
+Juneau	58.30	-134.42	America/Juneau
+Vancouver	49.28	-123.12	America/Vancouver
+Calgary	51.05	-114.07	America/Edmonton
+Edmonton	53.55	-113.49	America/Edmonton
+Regina	50.45	-104.62	America/Regina
+Winnipeg	49.90	-97.14	America/Winnipeg
+Toronto	43.65	-79.38	America/Toronto
+Ottawa	45.42	-75.70	America/Toronto
+Montreal	45.50	-73.57	America/Toronto
+Quebec City	46.81	-71.21	America/Toronto
+Halifax	44.65	-63.58	America/Halifax
+St. John's	47.56	-52.71	America/St_Johns
+Mexico City	19.43	-99.13	America/Mexico_City
+Guadalajara	20.66	-103.35	America/Mexico_City
+Monterrey	25.69	-100.32	America/Monterrey
+Tijuana	32.51	-117.04	America/Tijuana
+Cancún	21.16	-86.85	America/Cancun
+Guatemala City	14.63	-90.51	America/Guatemala
+San Salvador	13.69	-89.22	America/El_Salvador
+Tegucigalpa	14.07	-87.19	America/Tegucigalpa
+Managua	12.11	-86.24	America/Managua
+San José	9.93	-84.09	America/Costa_Rica
+Panama City	8.98	-79.52	America/Panama
+Havana	23.11	-82.37	America/Havana
+Kingston	17.97	-76.79	America/Jamaica
+Santo Domingo	18.49	-69.93	America/Santo_Domingo
+San Juan	18.47	-66.11	America/Puerto_Rico
+Port of Spain	10.65	-61.51	America/Port_of_Spain
+Bogotá	4.71	-74.07	America/Bogota
+Medellín	6.24	-75.58	America/Bogota
+Caracas	10.48	-66.90	America/Caracas
+Quito	-0.18	-78.47	America/Guayaquil
+Guayaquil	-2.17	-79.92	America/Guayaquil
+Lima	-12.05	-77.04	America/Lima
+La Paz	-16.49	-68.12	America/La_Paz
+Santiago	-33.45	-70.67	America/Santiago
+Buenos Aires	-34.60	-58.38	America/Argentina/Buenos_Aires
+Córdoba	-31.42	-64.18	America/Argentina/Cordoba
+Mendoza	-32.89	-68.85	America/Argentina/Mendoza
+Montevideo	-34.90	-56.16	America/Montevideo
+Asunción	-25.26	-57.58	America/Asuncion
+São Paulo	-23.55	-46.63	America/Sao_Paulo
+Rio de Janeiro	-22.91	-43.17	America/Sao_Paulo
+Belo Horizonte	-19.92	-43.94	America/Sao_Paulo
+Brasília	-15.79	-47.88	America/Sao_Paulo
+Curitiba	-25.43	-49.27	America/Sao_Paulo
+Porto Alegre	-30.03	-51.23	America/Sao_Paulo
+Salvador	-12.97	-38.50	America/Bahia
+Recife	-8.05	-34.88	America/Recife
+Fortaleza	-3.73	-38.53	America/Fortaleza
+Belém	-1.46	-48.50	America/Belem
+Manaus	-3.12	-60.02	America/Manaus
+`
@@ -0,0 +1,112 @@
+package speakerdeck
+
+import (
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+// DefaultMetadataKeys are the metadata keys recognised in talk descriptions, unless Client.MetadataKeys is set
+var DefaultMetadataKeys = []string{
+	"Hide",
+	"Location",
+	"Location-Hint",
+	"Event",
+	"Video",
+	"Co-Speakers",
+	"Language",
+	"Slides-Version",
+}
+
+// metadataLineRegexp matches "Key: value" lines
+var metadataLineRegexp = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9 _-]*?)\s*:\s*(.*?)\s*$`)
+
+// Metadata holds the "Key: value" metadata found in a talk description, e.g. "Location: Helsinki".
+// The keys are canonicalized like HTTP headers, so "co-speakers" is stored as "Co-Speakers".
+// A key may be given many times, in which case all values are kept in order.
+type Metadata map[string][]string
+
+// ParseMetadata parses the "Key: value" lines of text, and returns the metadata of the keys
+// in recognisedKeys. Other lines are ignored. The keys are matched ignoring case.
+func ParseMetadata(text string, recognisedKeys []string) Metadata {
+	m := Metadata{}
+	m.parse(text, recognisedKeys)
+	return m
+}
+
+// parse adds the metadata of the recognised keys found in text to m
+func (m Metadata) parse(text string, recognisedKeys []string) {
+	recognised := make(map[string]bool, len(recognisedKeys))
+	for _, key := range recognisedKeys {
+		recognised[canonicalMetadataKey(key)] = true
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		match := metadataLineRegexp.FindStringSubmatch(line)
+		if match == nil || len(match[2]) == 0 {
+			continue
+		}
+		key := canonicalMetadataKey(match[1])
+		if recognised[key] {
+			m[key] = append(m[key], match[2])
+		}
+	}
+}
+
+func canonicalMetadataKey(key string) string {
+	return textproto.CanonicalMIMEHeaderKey(strings.ReplaceAll(strings.TrimSpace(key), " ", "-"))
+}
+
+// Get returns the first value of key, or an empty string if it's not set
+func (m Metadata) Get(key string) string {
+	values := m.Values(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Values returns all values of key, in the order they were given
+func (m Metadata) Values(key string) []string {
+	return m[canonicalMetadataKey(key)]
+}
+
+// Has returns whether key is set
+func (m Metadata) Has(key string) bool {
+	return len(m.Values(key)) > 0
+}
+
+// Bool returns whether the first value of key is a true boolean, e.g. "true" or "1"
+func (m Metadata) Bool(key string) bool {
+	b, err := strconv.ParseBool(strings.ToLower(m.Get(key)))
+	return err == nil && b
+}
+
+// Int returns the first value of key as an integer. An error is returned if key isn't a number
+func (m Metadata) Int(key string) (int, error) {
+	return strconv.Atoi(m.Get(key))
+}
+
+// List returns all comma-separated items of all values of key, e.g. ["Jane Doe", "John Doe"]
+// for "Co-Speakers: Jane Doe, John Doe"
+func (m Metadata) List(key string) []string {
+	list := []string{}
+	for _, value := range m.Values(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// elementText returns the text of e, with line breaks (<br>) as newlines
+func elementText(e *colly.HTMLElement) string {
+	dom := e.DOM.Clone()
+	dom.Find("br").ReplaceWithHtml("\n")
+	return dom.Text()
+}
@@ -0,0 +1,86 @@
+package speakerdeck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		keys []string
+		want Metadata
+	}{
+		{
+			name: "recognised keys",
+			text: "A talk about Kubernetes.\nLocation: Helsinki, Finland\nEvent: KubeCon\nSpeaker notes: none",
+			keys: DefaultMetadataKeys,
+			want: Metadata{
+				"Location": {"Helsinki, Finland"},
+				"Event":    {"KubeCon"},
+			},
+		},
+		{
+			name: "keys are matched ignoring case and spaces",
+			text: "co speakers: Jane Doe, John Doe\n  HIDE :  true  ",
+			keys: DefaultMetadataKeys,
+			want: Metadata{
+				"Co-Speakers": {"Jane Doe, John Doe"},
+				"Hide":        {"true"},
+			},
+		},
+		{
+			name: "repeated keys keep all values in order",
+			text: "Video: https://youtu.be/a\nVideo: https://youtu.be/b",
+			keys: DefaultMetadataKeys,
+			want: Metadata{
+				"Video": {"https://youtu.be/a", "https://youtu.be/b"},
+			},
+		},
+		{
+			name: "empty values and links are ignored",
+			text: "Location:\nhttps://example.com\n12:30 lunch",
+			keys: DefaultMetadataKeys,
+			want: Metadata{},
+		},
+		{
+			name: "custom keys",
+			text: "Track: Cloud\nLocation: Online",
+			keys: []string{"track"},
+			want: Metadata{
+				"Track": {"Cloud"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMetadata(tt.text, tt.keys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataAccessors(t *testing.T) {
+	m := ParseMetadata("Hide: TRUE\nCo-Speakers: Jane Doe, , John Doe\nCo-Speakers: Alice\nSlides-Version: 3", DefaultMetadataKeys)
+
+	if !m.Bool("hide") {
+		t.Errorf("Bool(%q) = false, want true", "hide")
+	}
+	if m.Bool("Location") {
+		t.Errorf("Bool(%q) = true, want false", "Location")
+	}
+	if got := m.Get("co-speakers"); got != "Jane Doe, , John Doe" {
+		t.Errorf("Get(%q) = %q", "co-speakers", got)
+	}
+	if got, want := m.List("Co-Speakers"), []string{"Jane Doe", "John Doe", "Alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List(%q) = %v, want %v", "Co-Speakers", got, want)
+	}
+	if n, err := m.Int("Slides-Version"); err != nil || n != 3 {
+		t.Errorf("Int(%q) = %d, %v, want 3", "Slides-Version", n, err)
+	}
+	if m.Has("Location") {
+		t.Errorf("Has(%q) = true, want false", "Location")
+	}
+}
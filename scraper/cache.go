@@ -0,0 +1,241 @@
+package scraper
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CacheStatusHeader is set on responses passing through a ResponseCache, and describes whether the
+// response was served from the cache. Its value is one of CacheHit, CacheRevalidated or CacheMiss.
+const CacheStatusHeader = "X-Scraper-Cache"
+
+const (
+	// CacheHit means the response was served from the cache without contacting the server
+	CacheHit = "hit"
+	// CacheRevalidated means the cached response was served after the server confirmed it's still valid
+	CacheRevalidated = "revalidated"
+	// CacheMiss means the response was fetched from the server
+	CacheMiss = "miss"
+)
+
+// CachedResponse is a response stored in a CacheStore
+type CachedResponse struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"statusCode"`
+	// Header contains the response headers, including possible ETag and Last-Modified headers
+	Header http.Header `json:"header"`
+	// Body is the response body
+	Body []byte `json:"body"`
+	// Validated is the time the response was last fetched or revalidated
+	Validated time.Time `json:"validated"`
+}
+
+// CacheStore stores responses for a ResponseCache. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the response stored for key, if any
+	Get(key string) (*CachedResponse, bool)
+	// Set stores resp for key
+	Set(key string, resp *CachedResponse)
+}
+
+// ResponseCache caches the bodies of fetched pages together with their ETag and Last-Modified headers.
+// Cached responses younger than MaxAge are served directly, older ones are revalidated using a
+// conditional GET, and only downloaded again if they have changed. A ResponseCache is meant to be
+// shared between Scrape calls, so that repeated scrapes of the same pages are cheap.
+type ResponseCache struct {
+	// Store is where the responses are stored, e.g. NewMemoryStore(...) or NewDiskStore(...)
+	Store CacheStore
+
+	// MaxAge is how long a cached response is served without revalidating it. If zero, cached
+	// responses are always revalidated
+	MaxAge time.Duration
+}
+
+// Transport returns a http.RoundTripper caching the responses of the GET requests made through it.
+// The requests are made using next, or http.DefaultTransport if nil.
+func (c *ResponseCache) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{c, next}
+}
+
+type cacheTransport struct {
+	c    *ResponseCache
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	cached, ok := t.c.Store.Get(key)
+	if ok && time.Since(cached.Validated) < t.c.MaxAge {
+		return cached.response(req, CacheHit), nil
+	}
+
+	if ok {
+		etag, lastModified := cached.Header.Get("ETag"), cached.Header.Get("Last-Modified")
+		if len(etag) > 0 || len(lastModified) > 0 {
+			// Don't modify the caller's request
+			req = req.Clone(req.Context())
+			if len(etag) > 0 {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if len(lastModified) > 0 {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		revalidated := *cached
+		revalidated.Validated = time.Now()
+		t.c.Store.Set(key, &revalidated)
+		return revalidated.response(req, CacheRevalidated), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Header.Set(CacheStatusHeader, CacheMiss)
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.c.Store.Set(key, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Validated:  time.Now(),
+	})
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.Header.Set(CacheStatusHeader, CacheMiss)
+	return resp, nil
+}
+
+// response returns a new http.Response for the cached response, with the CacheStatusHeader set to status
+func (r *CachedResponse) response(req *http.Request, status string) *http.Response {
+	header := r.Header.Clone()
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// NewMemoryStore creates a CacheStore keeping at most maxEntries responses in memory. When full,
+// the least recently used response is evicted. If maxEntries is zero or less, the store is unbounded.
+func NewMemoryStore(maxEntries int) CacheStore {
+	return &memoryStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+type memoryStore struct {
+	mux        sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru contains the *memoryEntry values, the most recently used first
+	lru *list.List
+}
+
+type memoryEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+func (s *memoryStore) Get(key string) (*CachedResponse, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.lru.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).resp, true
+}
+
+func (s *memoryStore) Set(key string, resp *CachedResponse) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		elem.Value.(*memoryEntry).resp = resp
+		s.lru.MoveToFront(elem)
+		return
+	}
+	s.entries[key] = s.lru.PushFront(&memoryEntry{key, resp})
+
+	if s.maxEntries > 0 && s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// NewDiskStore creates a CacheStore persisting the responses as JSON files in dir
+func NewDiskStore(dir string) CacheStore {
+	return &diskStore{dir}
+}
+
+type diskStore struct {
+	dir string
+}
+
+func (s *diskStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (s *diskStore) Get(key string) (*CachedResponse, bool) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	resp := &CachedResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+func (s *diskStore) Set(key string, resp *CachedResponse) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	// Errors only mean that the response isn't cached
+	_ = writeFileAtomic(s.path(key), b)
+}
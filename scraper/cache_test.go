@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		// changed makes the server change the page between the first and second request
+		changed    bool
+		noETag     bool
+		wantStatus string
+		wantBody   string
+		// wantRequests is how many requests reached the server, in total
+		wantRequests int
+	}{
+		{
+			name:         "hit",
+			maxAge:       time.Hour,
+			wantStatus:   CacheHit,
+			wantBody:     "version 1",
+			wantRequests: 1,
+		},
+		{
+			name:         "revalidated",
+			wantStatus:   CacheRevalidated,
+			wantBody:     "version 1",
+			wantRequests: 2,
+		},
+		{
+			name:         "changed",
+			changed:      true,
+			wantStatus:   CacheMiss,
+			wantBody:     "version 2",
+			wantRequests: 2,
+		},
+		{
+			name:         "changed, but still fresh",
+			maxAge:       time.Hour,
+			changed:      true,
+			wantStatus:   CacheHit,
+			wantBody:     "version 1",
+			wantRequests: 1,
+		},
+		{
+			name:         "no validators",
+			noETag:       true,
+			wantStatus:   CacheMiss,
+			wantBody:     "version 1",
+			wantRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		for _, store := range []struct {
+			name  string
+			store CacheStore
+		}{
+			{"memory", NewMemoryStore(10)},
+			{"disk", NewDiskStore(t.TempDir())},
+		} {
+			t.Run(tt.name+"/"+store.name, func(t *testing.T) {
+				version, requests := 1, 0
+				s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					etag := fmt.Sprintf("%q", fmt.Sprint(version))
+					if !tt.noETag {
+						if r.Header.Get("If-None-Match") == etag {
+							w.WriteHeader(http.StatusNotModified)
+							return
+						}
+						w.Header().Set("ETag", etag)
+					}
+					fmt.Fprintf(w, "version %d", version)
+				}))
+				defer s.Close()
+
+				client := &http.Client{Transport: (&ResponseCache{Store: store.store, MaxAge: tt.maxAge}).Transport(nil)}
+				get := func() (string, string) {
+					resp, err := client.Get(s.URL)
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer resp.Body.Close()
+					body, err := ioutil.ReadAll(resp.Body)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return resp.Header.Get(CacheStatusHeader), string(body)
+				}
+
+				if status, body := get(); status != CacheMiss || body != "version 1" {
+					t.Fatalf("first request = %s %q, want %s %q", status, body, CacheMiss, "version 1")
+				}
+				if tt.changed {
+					version++
+				}
+				if status, body := get(); status != tt.wantStatus || body != tt.wantBody {
+					t.Errorf("second request = %s %q, want %s %q", status, body, tt.wantStatus, tt.wantBody)
+				}
+				if requests != tt.wantRequests {
+					t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+				}
+			})
+		}
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Set("a", &CachedResponse{Body: []byte("a")})
+	s.Set("b", &CachedResponse{Body: []byte("b")})
+	// Using a makes b the least recently used entry
+	s.Get("a")
+	s.Set("c", &CachedResponse{Body: []byte("c")})
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := s.Get(key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+}
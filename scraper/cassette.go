@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNotRecorded is returned by a strict Cassette in replay mode, for requests that have not been recorded
+var ErrNotRecorded = errors.New("request has not been recorded")
+
+// CassetteMode specifies whether a Cassette records or replays requests
+type CassetteMode int
+
+const (
+	// CassetteRecord makes all requests as usual, and saves the responses in the cassette directory
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay serves the responses saved in the cassette directory, instead of making the requests
+	CassetteReplay
+)
+
+// Cassette records every request/response made while scraping to a directory, and can later replay
+// them back exactly. This allows capturing a real scraping session once, and reproducing it e.g. in CI.
+type Cassette struct {
+	// Dir is the directory the requests are recorded to, or replayed from
+	Dir string
+
+	// Mode specifies whether requests are recorded or replayed
+	Mode CassetteMode
+
+	// Strict makes requests that haven't been recorded fail with ErrNotRecorded in replay mode.
+	// If Strict is false, such requests are made as usual (but not recorded).
+	Strict bool
+}
+
+// cassetteEntry is the on-disk format of a recorded request/response pair
+type cassetteEntry struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// Transport returns a http.RoundTripper recording or replaying the requests made through it.
+// Requests that are recorded or not replayed are made using next, or http.DefaultTransport if nil.
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cassetteTransport{c, next}
+}
+
+type cassetteTransport struct {
+	c    *Cassette
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.c.Mode == CassetteReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+func (t *cassetteTransport) replay(req *http.Request) (*http.Response, error) {
+	b, err := ioutil.ReadFile(t.c.path(req))
+	if os.IsNotExist(err) {
+		if t.c.Strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+		}
+		return t.next.RoundTrip(req)
+	} else if err != nil {
+		return nil, err
+	}
+
+	entry := &cassetteEntry{}
+	if err := json.Unmarshal(b, entry); err != nil {
+		return nil, fmt.Errorf("couldn't decode recorded response for %s %s: %w", req.Method, req.URL, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *cassetteTransport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// Give the caller an unread copy of the body
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	b, err := json.MarshalIndent(&cassetteEntry{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(t.c.path(req), b); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// writeFileAtomic writes b to path through a uniquely named temporary file in the same directory,
+// which is then renamed to path. That way concurrent readers never see a partially written file,
+// and concurrent writers of the same path don't clobber each other's temporary files.
+func writeFileAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op after a successful rename
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// TempFile creates the file with mode 0600
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// path returns the file path a request is recorded in. The file name contains a sanitized form
+// of the URL for readability, and a hash of the method and URL for uniqueness.
+func (c *Cassette) path(req *http.Request) string {
+	hash := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Host+req.URL.Path, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return filepath.Join(c.Dir, fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(hash[:8])))
+}
@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Path", r.URL.Path)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte("hello from " + r.URL.Path))
+	}))
+	defer s.Close()
+	dir := t.TempDir()
+
+	get := func(c *Cassette, path string) (*http.Response, string, error) {
+		resp, err := (&http.Client{Transport: c.Transport(nil)}).Get(s.URL + path)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return resp, string(body), err
+	}
+
+	recorder := &Cassette{Dir: dir, Mode: CassetteRecord}
+	for _, path := range []string{"/luxas", "/missing"} {
+		if _, body, err := get(recorder, path); err != nil || body != "hello from "+path {
+			t.Fatalf("recording %s = %q, %v", path, body, err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("made %d requests while recording, want 2", requests)
+	}
+
+	tests := []struct {
+		name         string
+		strict       bool
+		path         string
+		wantStatus   int
+		wantRequests int
+		wantErr      error
+	}{
+		{
+			name:         "recorded",
+			path:         "/luxas",
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "recorded error",
+			path:         "/missing",
+			wantStatus:   http.StatusNotFound,
+			wantRequests: 2,
+		},
+		{
+			name:         "not recorded",
+			path:         "/janedoe",
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+		},
+		{
+			name:         "not recorded, strict",
+			strict:       true,
+			path:         "/janedoe",
+			wantRequests: 2,
+			wantErr:      ErrNotRecorded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 2
+			resp, body, err := get(&Cassette{Dir: dir, Mode: CassetteReplay, Strict: tt.strict}, tt.path)
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus || body != "hello from "+tt.path || resp.Header.Get("X-Path") != tt.path {
+				t.Errorf("replayed %d %q (X-Path %q), want %d %q", resp.StatusCode, body, resp.Header.Get("X-Path"), tt.wantStatus, "hello from "+tt.path)
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomicConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "entry.json")
+
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, 20)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- writeFileAtomic(path, []byte(fmt.Sprintf(`{"writer": %d}`, i)))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("writeFileAtomic() error = %v", err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), `{"writer": `) || !strings.HasSuffix(string(b), "}") {
+		t.Errorf("file contains %q, want the complete content of one writer", b)
+	}
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("directory contains %d files, want no temporary files left", len(files))
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("file mode = %v, %v, want 0644", info.Mode().Perm(), err)
+	}
+}
+
+func TestCassetteConcurrentRecording(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer s.Close()
+	client := &http.Client{Transport: (&Cassette{Dir: t.TempDir(), Mode: CassetteRecord}).Transport(nil)}
+
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(s.URL + "/luxas")
+			if err == nil {
+				resp.Body.Close()
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("recording the same request concurrently failed: %v", err)
+		}
+	}
+}
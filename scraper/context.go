@@ -0,0 +1,39 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gocolly/colly"
+)
+
+// contextKey is the key under which the context.Context of ScrapeContext is stored in the colly.Context
+const contextKey = "scraper.context"
+
+// Context returns the context.Context the page of e is scraped with. Hook handlers may use it
+// for cancelling their own (possibly long-running) work. If there is no such context,
+// context.Background() is returned.
+func Context(e *colly.HTMLElement) context.Context {
+	if e.Request != nil && e.Request.Ctx != nil {
+		if ctx, ok := e.Request.Ctx.GetAny(contextKey).(context.Context); ok {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// contextTransport makes all requests passing through it use ctx, so that they're cancelled when
+// ctx is done. If next is nil, http.DefaultTransport is used for the actual requests.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,146 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotFound matches errors for pages that don't exist (HTTP 404 or 410), using errors.Is
+	ErrNotFound = errors.New("page not found")
+	// ErrRateLimited matches errors for pages that couldn't be fetched due to rate limiting (HTTP 429), using errors.Is
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamUnavailable matches errors for pages that couldn't be fetched due to a server error (HTTP 5xx)
+	// or a network error, using errors.Is
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// HTTPError is returned when a page could not be fetched
+type HTTPError struct {
+	// URL is the URL of the page that could not be fetched
+	URL string
+	// StatusCode is the HTTP status code of the response, or zero if there was no response at all
+	StatusCode int
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements error
+func (e *HTTPError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("couldn't fetch page %q: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("couldn't fetch page %q: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the error match ErrNotFound, ErrRateLimited or ErrUpstreamUnavailable depending on the status code
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUpstreamUnavailable:
+		if e.StatusCode == 0 {
+			return !errors.Is(e.Err, context.Canceled) && !errors.Is(e.Err, context.DeadlineExceeded)
+		}
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// ParseError is returned by hook handlers when the content of an element could not be parsed.
+// If DOMPath is empty, Scrape sets it to the DOMPath of the hook.
+type ParseError struct {
+	// DOMPath is the DOMPath of the element that couldn't be parsed
+	DOMPath string
+	// Text is the raw text that couldn't be parsed
+	Text string
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("couldn't parse %q at dompath %q: %v", e.Text, e.DOMPath, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// NoMatchError is returned when the DOMPath of a hook didn't match any element on a page
+type NoMatchError struct {
+	// URL is the URL of the page
+	URL string
+	// DOMPath is the DOMPath that didn't match anything
+	DOMPath string
+}
+
+// Error implements error
+func (e *NoMatchError) Error() string {
+	return fmt.Sprintf("dompath %q matched nothing on page %q", e.DOMPath, e.URL)
+}
+
+// HookError is returned when the handler of a hook fails for a page
+type HookError struct {
+	// URL is the URL of the page the hook failed for
+	URL string
+	// DOMPath is the DOMPath of the failed hook
+	DOMPath string
+	// Err is the error returned by the handler
+	Err error
+}
+
+// Error implements error
+func (e *HookError) Error() string {
+	return fmt.Sprintf("error while handling dompath %q for page %q: %v", e.DOMPath, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is returned by Scrape when one or many hooks failed. The individual errors can be
+// inspected using errors.Is and errors.As.
+type Errors []error
+
+// Error implements error
+func (e Errors) Error() string {
+	return fmt.Sprintf("errors occured during scraping: %v", []error(e))
+}
+
+// Unwrap returns the individual errors. Only Go 1.20 and newer use it in errors.Is and errors.As,
+// hence Is and As are implemented explicitly as well
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Is reports whether any of the individual errors matches target
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the individual errors that matches target, and if so, sets target to it
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
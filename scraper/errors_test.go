@@ -0,0 +1,98 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       error
+	}{
+		{name: "not found", statusCode: http.StatusNotFound, want: ErrNotFound},
+		{name: "gone", statusCode: http.StatusGone, want: ErrNotFound},
+		{name: "rate limited", statusCode: http.StatusTooManyRequests, want: ErrRateLimited},
+		{name: "internal server error", statusCode: http.StatusInternalServerError, want: ErrUpstreamUnavailable},
+		{name: "bad gateway", statusCode: http.StatusBadGateway, want: ErrUpstreamUnavailable},
+		{name: "service unavailable", statusCode: http.StatusServiceUnavailable, want: ErrUpstreamUnavailable},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: ErrUpstreamUnavailable},
+		{name: "forbidden", statusCode: http.StatusForbidden},
+		{name: "cancelled", err: context.Canceled},
+		{name: "timed out", err: fmt.Errorf("request failed: %w", context.DeadlineExceeded)},
+	}
+	sentinels := []error{ErrNotFound, ErrRateLimited, ErrUpstreamUnavailable}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(&HTTPError{URL: "https://speakerdeck.com/luxas", StatusCode: tt.statusCode, Err: tt.err})
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", err, sentinel, got, !got)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorsIsAs(t *testing.T) {
+	notFound := &HTTPError{URL: "https://speakerdeck.com/nobody", StatusCode: http.StatusNotFound}
+	noMatch := &NoMatchError{URL: "https://speakerdeck.com/luxas", DOMPath: ".talks"}
+	parseErr := &HookError{DOMPath: ".stars", Err: &ParseError{Text: "many", Err: errors.New("invalid")}}
+
+	tests := []struct {
+		name        string
+		errs        Errors
+		wantIs      []error
+		wantIsNot   []error
+		wantNoMatch *NoMatchError
+		wantParse   bool
+	}{
+		{
+			name:      "empty",
+			wantIsNot: []error{ErrNotFound, ErrRateLimited},
+		},
+		{
+			name:        "mixed",
+			errs:        Errors{noMatch, notFound},
+			wantIs:      []error{ErrNotFound},
+			wantIsNot:   []error{ErrRateLimited, ErrUpstreamUnavailable},
+			wantNoMatch: noMatch,
+		},
+		{
+			name:      "wrapped",
+			errs:      Errors{parseErr},
+			wantIsNot: []error{ErrNotFound},
+			wantParse: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wrap the errors like callers do, to make sure they are still found
+			err := fmt.Errorf("scraping failed: %w", tt.errs)
+			for _, target := range tt.wantIs {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v) = false, want true", target)
+				}
+			}
+			for _, target := range tt.wantIsNot {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v) = true, want false", target)
+				}
+			}
+			var noMatchErr *NoMatchError
+			if found := errors.As(err, &noMatchErr); found != (tt.wantNoMatch != nil) || noMatchErr != tt.wantNoMatch {
+				t.Errorf("errors.As(*NoMatchError) = %v, %v, want %v", found, noMatchErr, tt.wantNoMatch)
+			}
+			var parseErr *ParseError
+			if found := errors.As(err, &parseErr); found != tt.wantParse {
+				t.Errorf("errors.As(*ParseError) = %v, want %v", found, tt.wantParse)
+			}
+		})
+	}
+}
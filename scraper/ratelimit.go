@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+	"golang.org/x/time/rate"
+)
+
+// DefaultMaxConcurrency is the default amount of pages fetched at the same time, if not set in RateLimit
+const DefaultMaxConcurrency = 8
+
+// RateLimit limits how fast and how concurrently pages are fetched. A RateLimit is meant to be shared
+// between Scrape calls (e.g. by passing the same ScrapeOptions to all of them), so that the limits
+// apply to all requests made, not only the ones of a single Scrape call.
+type RateLimit struct {
+	// MaxConcurrency limits how many pages may be fetched at the same time. This is applied to the
+	// requests made through the Transport, which holds a slot of a semaphore shared by all Scrape
+	// calls for each request, to the Parallelism of the colly.LimitRule of each Scrape call, and to
+	// how many sub-scrapes fan-out functions like speakerdeck.ScrapeTalks run in parallel.
+	// If zero, DefaultMaxConcurrency is used
+	MaxConcurrency int
+
+	// RequestsPerSecond limits the rate of requests made per host. If zero, the rate is not limited
+	RequestsPerSecond float64
+
+	// Burst is the maximum amount of requests per host that may be made at once, before being
+	// limited by RequestsPerSecond. If zero, 1 is used
+	Burst int
+
+	// RandomDelay is the maximum extra, randomized delay after each request
+	RandomDelay time.Duration
+
+	mux      sync.Mutex
+	sem      chan struct{}
+	limiters map[string]*rate.Limiter
+}
+
+// Concurrency returns the maximum amount of pages fetched at the same time. r may be nil,
+// in which case DefaultMaxConcurrency is returned.
+func (r *RateLimit) Concurrency() int {
+	if r == nil || r.MaxConcurrency <= 0 {
+		return DefaultMaxConcurrency
+	}
+	return r.MaxConcurrency
+}
+
+// Transport returns a http.RoundTripper applying the limits to the requests made through it.
+// The requests are made using next, or http.DefaultTransport if nil.
+func (r *RateLimit) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitTransport{r, next}
+}
+
+// limitRule returns the colly.LimitRule corresponding to r, applying MaxConcurrency as the Parallelism
+// of the collector, and RandomDelay. As the Parallelism of a colly.LimitRule only applies to a single
+// collector, the limit across all Scrape calls is enforced by the semaphore of the Transport.
+func (r *RateLimit) limitRule() *colly.LimitRule {
+	return &colly.LimitRule{
+		DomainGlob:  "*",
+		Parallelism: r.Concurrency(),
+		RandomDelay: r.RandomDelay,
+	}
+}
+
+// acquire blocks until a request slot is available, or until done is closed. The returned
+// function releases the slot, and is nil if done was closed first.
+func (r *RateLimit) acquire(done <-chan struct{}) func() {
+	r.mux.Lock()
+	if r.sem == nil {
+		r.sem = make(chan struct{}, r.Concurrency())
+	}
+	sem := r.sem
+	r.mux.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }
+	case <-done:
+		return nil
+	}
+}
+
+// limiter returns the rate limiter for host, or nil if the rate isn't limited
+func (r *RateLimit) limiter(host string) *rate.Limiter {
+	if r.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.limiters == nil {
+		r.limiters = map[string]*rate.Limiter{}
+	}
+	l, ok := r.limiters[host]
+	if !ok {
+		burst := r.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(r.RequestsPerSecond), burst)
+		r.limiters[host] = l
+	}
+	return l
+}
+
+type rateLimitTransport struct {
+	r    *RateLimit
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	release := t.r.acquire(ctx.Done())
+	if release == nil {
+		return nil, ctx.Err()
+	}
+	defer release()
+
+	if l := t.r.limiter(req.URL.Host); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
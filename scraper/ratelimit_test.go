@@ -0,0 +1,167 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitConcurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *RateLimit
+		want int
+	}{
+		{name: "nil", r: nil, want: DefaultMaxConcurrency},
+		{name: "unset", r: &RateLimit{}, want: DefaultMaxConcurrency},
+		{name: "negative", r: &RateLimit{MaxConcurrency: -1}, want: DefaultMaxConcurrency},
+		{name: "set", r: &RateLimit{MaxConcurrency: 3}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Concurrency(); got != tt.want {
+				t.Errorf("Concurrency() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitLimitRule(t *testing.T) {
+	r := &RateLimit{MaxConcurrency: 3, RandomDelay: time.Second}
+	rule := r.limitRule()
+	if rule.DomainGlob != "*" || rule.Parallelism != 3 || rule.RandomDelay != time.Second {
+		t.Errorf("limitRule() = %+v, want all domains with Parallelism 3 and RandomDelay 1s", rule)
+	}
+}
+
+func TestRateLimitAcquire(t *testing.T) {
+	r := &RateLimit{MaxConcurrency: 1}
+	release := r.acquire(nil)
+	if release == nil {
+		t.Fatal("acquire() didn't get the free slot")
+	}
+
+	// All slots are taken, so acquire returns nil when done is closed
+	done := make(chan struct{})
+	close(done)
+	if r.acquire(done) != nil {
+		t.Error("acquire() got a slot while all were taken")
+	}
+
+	release()
+	if release := r.acquire(nil); release == nil {
+		t.Error("acquire() didn't get the released slot")
+	} else {
+		release()
+	}
+}
+
+// testFanOut runs Scrape calls for n pages concurrently, sharing opts, against a server that takes
+// delay to respond. The maximum amount of requests in flight at the same time is returned.
+func testFanOut(t *testing.T, n int, delay time.Duration, opts *ScrapeOptions) int {
+	mux := &sync.Mutex{}
+	inFlight, maxInFlight := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mux.Unlock()
+
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(reportFixture))
+
+		mux.Lock()
+		inFlight--
+		mux.Unlock()
+	}))
+	defer srv.Close()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every Scrape call uses its own collector
+			if _, err := Scrape(srv.URL, &reportScraper{hooks: []Hook{{DOMPath: "h1.title", Handler: noop}}}, opts); err != nil {
+				t.Errorf("Scrape() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	return maxInFlight
+}
+
+func TestRateLimitMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		rateLimit   *RateLimit
+		wantAtMost  int
+		wantAtLeast int
+	}{
+		{
+			name:        "not limited",
+			wantAtLeast: 2,
+			wantAtMost:  6,
+		},
+		{
+			name:        "shared between collectors",
+			rateLimit:   &RateLimit{MaxConcurrency: 2},
+			wantAtLeast: 1,
+			wantAtMost:  2,
+		},
+		{
+			name:        "one at a time",
+			rateLimit:   &RateLimit{MaxConcurrency: 1},
+			wantAtLeast: 1,
+			wantAtMost:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testFanOut(t, 6, 50*time.Millisecond, &ScrapeOptions{RateLimit: tt.rateLimit})
+			if got < tt.wantAtLeast || got > tt.wantAtMost {
+				t.Errorf("%d requests were in flight at once, want %d-%d", got, tt.wantAtLeast, tt.wantAtMost)
+			}
+		})
+	}
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit *RateLimit
+		// wantMin is the minimum time 5 requests are expected to take
+		wantMin time.Duration
+	}{
+		{
+			name:      "20 per second",
+			rateLimit: &RateLimit{RequestsPerSecond: 20},
+			// The first request is made right away, the 4 others at 50ms intervals
+			wantMin: 200 * time.Millisecond,
+		},
+		{
+			name:      "burst",
+			rateLimit: &RateLimit{RequestsPerSecond: 20, Burst: 3},
+			// The 3 first requests are made right away, the 2 others at 50ms intervals
+			wantMin: 100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			testFanOut(t, 5, 0, &ScrapeOptions{RateLimit: tt.rateLimit})
+			if took := time.Since(start); took < tt.wantMin {
+				t.Errorf("5 requests took %v, want at least %v", took, tt.wantMin)
+			}
+			// Requests aren't held back longer than the rate requires
+			if took := time.Since(start); took > tt.wantMin+time.Second {
+				t.Errorf("5 requests took %v, want close to %v", took, tt.wantMin)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package scraper
+
+import "fmt"
+
+// PageReport describes how the hooks of a Scraper (and its extensions) matched a scraped page.
+// This can be used for detecting when the markup of the scraped site has drifted from what the
+// hooks expect.
+type PageReport struct {
+	// Scraper is the name of the Scraper that scraped the page
+	Scraper string
+	// URL is the URL of the page
+	URL string
+	// Hooks describes how each hook matched the page, in the order the hooks were registered
+	Hooks []HookReport
+	// Cache is CacheHit, CacheRevalidated or CacheMiss if the page passed through a ResponseCache, otherwise empty
+	Cache string
+}
+
+// HookReport describes how the DOMPath of a hook matched a page
+type HookReport struct {
+	// DOMPath is the DOMPath of the hook
+	DOMPath string
+	// Required is true if the hook is required
+	Required bool
+	// MinMatches is the minimum amount of expected matches
+	MinMatches int
+	// MaxMatches is the maximum amount of expected matches, or zero if unlimited
+	MaxMatches int
+	// Matches is the amount of elements the DOMPath matched
+	Matches int
+}
+
+func newPageReport(scraperName, url string, hooks []Hook, matches []int) *PageReport {
+	r := &PageReport{
+		Scraper: scraperName,
+		URL:     url,
+		Hooks:   make([]HookReport, 0, len(hooks)),
+	}
+	for i, h := range hooks {
+		minMatches := h.MinMatches
+		if h.Required && minMatches == 0 {
+			minMatches = 1
+		}
+		r.Hooks = append(r.Hooks, HookReport{
+			DOMPath:    h.DOMPath,
+			Required:   h.Required,
+			MinMatches: minMatches,
+			MaxMatches: h.MaxMatches,
+			Matches:    matches[i],
+		})
+	}
+	return r
+}
+
+// Unmatched returns the hooks that never fired on the page
+func (r *PageReport) Unmatched() []HookReport {
+	hooks := []HookReport{}
+	for _, h := range r.Hooks {
+		if h.Matches == 0 {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// Drifted returns the hooks that didn't match the expected amount of elements on the page
+func (r *PageReport) Drifted() []HookReport {
+	hooks := []HookReport{}
+	for _, h := range r.Hooks {
+		if !h.OK() {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// OK returns true if the hook matched the expected amount of elements
+func (h HookReport) OK() bool {
+	return h.Matches >= h.MinMatches && (h.MaxMatches == 0 || h.Matches <= h.MaxMatches)
+}
+
+// Expected returns a human-readable form of the expected amount of matches, e.g. "1", "0..1" or "1..*"
+func (h HookReport) Expected() string {
+	switch {
+	case h.MaxMatches == 0:
+		return fmt.Sprintf("%d..*", h.MinMatches)
+	case h.MinMatches == h.MaxMatches:
+		return fmt.Sprintf("%d", h.MinMatches)
+	default:
+		return fmt.Sprintf("%d..%d", h.MinMatches, h.MaxMatches)
+	}
+}
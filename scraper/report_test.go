@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gocolly/colly"
+)
+
+const reportFixture = `<html><body>
+<h1 class="title">Talks</h1>
+<ul class="talks"><li>One</li><li>Two</li><li>Three</li></ul>
+</body></html>`
+
+// reportScraper scrapes reportFixture using the given hooks, which do nothing
+type reportScraper struct {
+	hooks []Hook
+}
+
+func (s *reportScraper) Name() string { return "ReportScraper" }
+
+func (s *reportScraper) Hooks() []Hook { return s.hooks }
+
+func (s *reportScraper) InitialData() interface{} { return &struct{}{} }
+
+func noop(e *colly.HTMLElement, data interface{}) (*string, error) { return nil, nil }
+
+func TestPageReport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(reportFixture))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		hooks         []Hook
+		wantMatches   []int
+		wantUnmatched []string
+		wantDrifted   []string
+		// wantNoMatch are the DOMPaths expected to fail the scrape with a *NoMatchError
+		wantNoMatch []string
+	}{
+		{
+			name: "all matched",
+			hooks: []Hook{
+				{DOMPath: "h1.title", Required: true, MaxMatches: 1},
+				{DOMPath: ".talks li", MinMatches: 3},
+			},
+			wantMatches:   []int{1, 3},
+			wantUnmatched: []string{},
+			wantDrifted:   []string{},
+		},
+		{
+			name: "optional hook matched nothing",
+			hooks: []Hook{
+				{DOMPath: "h1.title"},
+				{DOMPath: ".description"},
+			},
+			wantMatches:   []int{1, 0},
+			wantUnmatched: []string{".description"},
+			wantDrifted:   []string{},
+		},
+		{
+			name: "missing selector drifted",
+			hooks: []Hook{
+				{DOMPath: "h1.title", Required: true},
+				{DOMPath: ".deck-title", Required: true},
+			},
+			wantMatches:   []int{1, 0},
+			wantUnmatched: []string{".deck-title"},
+			wantDrifted:   []string{".deck-title"},
+			wantNoMatch:   []string{".deck-title"},
+		},
+		{
+			name: "too few matches",
+			hooks: []Hook{
+				{DOMPath: ".talks li", Required: true, MinMatches: 4},
+			},
+			wantMatches:   []int{3},
+			wantUnmatched: []string{},
+			wantDrifted:   []string{".talks li"},
+			wantNoMatch:   []string{".talks li"},
+		},
+		{
+			name: "too many matches is only reported",
+			hooks: []Hook{
+				{DOMPath: ".talks li", Required: true, MaxMatches: 2},
+			},
+			wantMatches:   []int{3},
+			wantUnmatched: []string{},
+			wantDrifted:   []string{".talks li"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.hooks {
+				tt.hooks[i].Handler = noop
+			}
+			var reports []*PageReport
+			_, err := Scrape(srv.URL, &reportScraper{tt.hooks}, &ScrapeOptions{
+				OnPage: func(r *PageReport) { reports = append(reports, r) },
+			})
+
+			noMatch := []string{}
+			for _, e := range errsOf(err) {
+				var noMatchErr *NoMatchError
+				if !errors.As(e, &noMatchErr) {
+					t.Fatalf("Scrape() error = %v, want only *NoMatchErrors", e)
+				}
+				noMatch = append(noMatch, noMatchErr.DOMPath)
+			}
+			if len(noMatch) != len(tt.wantNoMatch) || (len(noMatch) > 0 && !reflect.DeepEqual(noMatch, tt.wantNoMatch)) {
+				t.Errorf("no matches for %q, want %q", noMatch, tt.wantNoMatch)
+			}
+
+			if len(reports) != 1 {
+				t.Fatalf("got %d reports, want 1", len(reports))
+			}
+			r := reports[0]
+			if r.Scraper != "ReportScraper" || r.URL != srv.URL {
+				t.Errorf("report is for %s %q, want ReportScraper %q", r.Scraper, r.URL, srv.URL)
+			}
+			matches := []int{}
+			for _, h := range r.Hooks {
+				matches = append(matches, h.Matches)
+			}
+			if !reflect.DeepEqual(matches, tt.wantMatches) {
+				t.Errorf("matches = %v, want %v", matches, tt.wantMatches)
+			}
+			if got := domPaths(r.Unmatched()); !reflect.DeepEqual(got, tt.wantUnmatched) {
+				t.Errorf("Unmatched() = %q, want %q", got, tt.wantUnmatched)
+			}
+			if got := domPaths(r.Drifted()); !reflect.DeepEqual(got, tt.wantDrifted) {
+				t.Errorf("Drifted() = %q, want %q", got, tt.wantDrifted)
+			}
+		})
+	}
+}
+
+func TestHookReportExpected(t *testing.T) {
+	tests := []struct {
+		report HookReport
+		want   string
+		wantOK bool
+	}{
+		{report: HookReport{Matches: 0}, want: "0..*", wantOK: true},
+		{report: HookReport{MinMatches: 1, Matches: 5}, want: "1..*", wantOK: true},
+		{report: HookReport{MinMatches: 1, Matches: 0}, want: "1..*", wantOK: false},
+		{report: HookReport{MinMatches: 1, MaxMatches: 1, Matches: 1}, want: "1", wantOK: true},
+		{report: HookReport{MinMatches: 1, MaxMatches: 1, Matches: 2}, want: "1", wantOK: false},
+		{report: HookReport{MaxMatches: 1, Matches: 0}, want: "0..1", wantOK: true},
+	}
+	for _, tt := range tests {
+		if got := tt.report.Expected(); got != tt.want {
+			t.Errorf("%+v: Expected() = %q, want %q", tt.report, got, tt.want)
+		}
+		if got := tt.report.OK(); got != tt.wantOK {
+			t.Errorf("%+v: OK() = %v, want %v", tt.report, got, tt.wantOK)
+		}
+	}
+}
+
+// errsOf returns the individual errors of err, if it's Errors
+func errsOf(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	return []error{err}
+}
+
+func domPaths(hooks []HookReport) []string {
+	paths := []string{}
+	for _, h := range hooks {
+		paths = append(paths, h.DOMPath)
+	}
+	return paths
+}
@@ -0,0 +1,183 @@
+package scraper
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxAttempts is the default amount of attempts made per page, if not set in RetryPolicy
+	DefaultMaxAttempts = 3
+	// DefaultInitialBackoff is the default backoff before the first retry, if not set in RetryPolicy
+	DefaultInitialBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the default upper limit for the backoff between retries, if not set in RetryPolicy
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried, if not set in RetryPolicy
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy describes how failed requests are retried. A request is retried if it fails with a network
+// error, or with one of the RetryableStatusCodes. The backoff between attempts grows exponentially
+// with some random jitter, unless the server specifies a Retry-After header, which is then respected.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum amount of attempts made per page, including the first one.
+	// If zero, DefaultMaxAttempts is used
+	MaxAttempts int
+
+	// InitialBackoff is the backoff before the first retry. If zero, DefaultInitialBackoff is used
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper limit for the backoff between retries. If the server asks to retry after
+	// a longer duration than this using Retry-After, the request is not retried. If zero, DefaultMaxBackoff is used
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the backoff grows with between retries. If zero, 2 is used
+	Multiplier float64
+
+	// Jitter is the fraction of the backoff that is randomized, e.g. 0.2 randomizes a 1s backoff
+	// to be between 0.8s and 1.2s. Should be between 0 and 1
+	Jitter float64
+
+	// RetryableStatusCodes are the HTTP status codes that should be retried. If nil,
+	// DefaultRetryableStatusCodes is used
+	RetryableStatusCodes []int
+}
+
+// Transport returns a http.RoundTripper retrying the requests made through it according to the policy.
+// The requests are made using next, or http.DefaultTransport if nil.
+func (p *RetryPolicy) Transport(next http.RoundTripper) http.RoundTripper {
+	return p.transport(next, log.StandardLogger())
+}
+
+func (p *RetryPolicy) transport(next http.RoundTripper, logger *log.Logger) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{p, next, logger}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// backoff returns how long to wait before the given retry, starting from 1
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	initial, multiplier := p.InitialBackoff, p.Multiplier
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(retry-1))
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if max := float64(p.maxBackoff()); d > max {
+		d = max
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) retryable(statusCode int) bool {
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = DefaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+type retryTransport struct {
+	p      *RetryPolicy
+	next   http.RoundTripper
+	logger *log.Logger
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.p.maxAttempts() || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			wait = t.p.backoff(attempt)
+			t.logger.Warnf("request %q failed, retrying in %s: %v", req.URL, wait, err)
+		case t.p.retryable(resp.StatusCode):
+			wait = t.p.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				// Give up if the server wants us to wait for longer than allowed
+				if retryAfter > t.p.maxBackoff() {
+					return resp, nil
+				}
+				wait = retryAfter
+			}
+			t.logger.Warnf("request %q failed with status %d, retrying in %s", req.URL, resp.StatusCode, wait)
+			// Drain the body, so the connection can be reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, which is either an amount of seconds or a HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
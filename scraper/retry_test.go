@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "zero seconds", value: "0", want: 0, wantOK: true},
+		{name: "negative seconds", value: "-1"},
+		{name: "past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "garbage", value: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := parseRetryAfter(future); !ok || got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %s, %v, want about an hour", future, got, ok)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   []time.Duration
+	}{
+		{
+			name:   "defaults",
+			policy: RetryPolicy{},
+			want:   []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:   "custom multiplier",
+			policy: RetryPolicy{InitialBackoff: time.Second, Multiplier: 3},
+			want:   []time.Duration{time.Second, 3 * time.Second, 9 * time.Second},
+		},
+		{
+			name:   "capped by MaxBackoff",
+			policy: RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second},
+			want:   []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.policy.backoff(i + 1); got != want {
+					t.Errorf("backoff(%d) = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	p := &RetryPolicy{InitialBackoff: time.Second, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter = %s, want between 0.8s and 1.2s", got)
+		}
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		retryAfter string
+		policy     RetryPolicy
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "success",
+			statuses:   []int{http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  1,
+		},
+		{
+			name:       "retried until success",
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "gives up after MaxAttempts",
+			statuses:   []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			policy:     RetryPolicy{MaxAttempts: 2},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  2,
+		},
+		{
+			name:       "not retryable",
+			statuses:   []int{http.StatusNotFound, http.StatusOK},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		{
+			name:       "Retry-After respected",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "0",
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "Retry-After longer than MaxBackoff",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "3600",
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls]
+				calls++
+				if len(tt.retryAfter) > 0 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer s.Close()
+
+			policy := tt.policy
+			policy.InitialBackoff = time.Millisecond
+			resp, err := (&http.Client{Transport: policy.Transport(nil)}).Get(s.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
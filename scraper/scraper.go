@@ -38,12 +38,17 @@ interface, e.g.
 		log.Printf("Important message is: %s", myData.ImportantMessage)
 	}
 
+For a type-safe variant of this API using generics, which passes *MyScrapedData to the handlers directly,
+see the github.com/luxas/speakerdeck-api/scraper/typed package.
 */
 package scraper
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gocolly/colly"
 	log "github.com/sirupsen/logrus"
@@ -63,6 +68,17 @@ type Hook struct {
 
 	// Handler specifies the handler to be invoked for all of the elements on the HTML page matched by the CSS selector
 	Handler HookFn
+
+	// Required makes Scrape fail with a *NoMatchError for pages where DOMPath matches less than
+	// MinMatches elements (or no elements at all, if MinMatches is zero)
+	Required bool
+
+	// MinMatches is the minimum amount of elements DOMPath is expected to match per page
+	MinMatches int
+
+	// MaxMatches is the maximum amount of elements DOMPath is expected to match per page. If zero,
+	// there is no upper limit. Exceeding it doesn't fail the scrape, but is reported in the PageReport
+	MaxMatches int
 }
 
 // Scraper is an interface which scraping implementations should implement.
@@ -96,6 +112,32 @@ type ScrapeOptions struct {
 	Extensions []Extension
 	// LogLevel specifies the logrus log level for the Scrape() function
 	LogLevel *log.Level
+	// Transport specifies the http.RoundTripper used for all requests. If nil, http.DefaultTransport is used
+	Transport http.RoundTripper
+	// UserAgent overrides the User-Agent header sent with all requests, if set
+	UserAgent string
+	// Timeout overrides the default request timeout of the collector (10 seconds), if set
+	Timeout time.Duration
+	// Cassette optionally records all requests made to a directory, or replays them from there
+	Cassette *Cassette
+	// Cache optionally caches the fetched pages, and revalidates them using conditional requests
+	Cache *ResponseCache
+	// RateLimit optionally limits how fast and how concurrently pages are fetched
+	RateLimit *RateLimit
+	// Retry optionally specifies how failed requests are retried. If nil, requests are not retried
+	Retry *RetryPolicy
+	// MaxFailures is how many sub-scrapes of fan-out functions like speakerdeck.ScrapeTalks may fail
+	// without aborting the whole operation; it's aborted when one more fails. If zero, any amount of
+	// failures is tolerated, unless FailFast is set
+	MaxFailures int
+	// FailFast aborts fan-out functions like speakerdeck.ScrapeTalks on the first failed sub-scrape,
+	// i.e. tolerates no failures. It takes precedence over MaxFailures
+	FailFast bool
+	// OnPage is called with a report of how the hooks matched each scraped page, if set
+	OnPage func(*PageReport)
+	// MaxPages limits how many pages are visited in one Scrape() call, including the first page.
+	// Further pages returned by the hooks are ignored. If zero, all pages are visited
+	MaxPages int
 }
 
 // Scrape takes in a Scraper struct, an URL to scrape, and optionally extra options.
@@ -103,52 +145,160 @@ type ScrapeOptions struct {
 // shares the Scraper.InitialData() struct pointer between them. The return value is that
 // struct pointer, and/or possibly an error.
 func Scrape(url string, s Scraper, opts *ScrapeOptions) (interface{}, error) {
+	return ScrapeContext(context.Background(), url, s, opts)
+}
+
+// ScrapeContext works like Scrape, but stops scraping when ctx is done. In-flight requests
+// are cancelled, and no further pages returned by the hooks are visited. The context is
+// available to the hook handlers through Context(e).
+func ScrapeContext(ctx context.Context, url string, s Scraper, opts *ScrapeOptions) (interface{}, error) {
 	c := colly.NewCollector()
 	mux := &sync.Mutex{}
 	data := s.InitialData()
 	logger := log.New()
 	allHooks := s.Hooks()
 
+	var transport http.RoundTripper
 	if opts != nil {
 		if opts.LogLevel != nil {
 			logger.SetLevel(*opts.LogLevel)
 		}
+		if len(opts.UserAgent) > 0 {
+			c.UserAgent = opts.UserAgent
+		}
+		if opts.Timeout > 0 {
+			c.SetRequestTimeout(opts.Timeout)
+		}
+		transport = opts.Transport
+		if opts.RateLimit != nil {
+			transport = opts.RateLimit.Transport(transport)
+			if err := c.Limit(opts.RateLimit.limitRule()); err != nil {
+				return nil, err
+			}
+		}
+		// Every retry passes through the rate limiter
+		if opts.Retry != nil {
+			transport = opts.Retry.transport(transport, logger)
+		}
+		// Cached responses don't need to be retried or rate limited
+		if opts.Cache != nil {
+			transport = opts.Cache.Transport(transport)
+		}
+		// The cassette wraps the rate limiter, as replayed requests don't need to be limited
+		if opts.Cassette != nil {
+			transport = opts.Cassette.Transport(transport)
+		}
 
 		for _, ext := range opts.Extensions {
 			allHooks = append(allHooks, ext.Hook())
 		}
 	}
+	c.WithTransport(&contextTransport{ctx: ctx, next: transport})
 
 	errs := []error{}
-	for _, h := range allHooks {
-		func(hook Hook) {
+	// queue contains the pages left to visit, and visited the pages that have been queued already
+	queue := []string{url}
+	visited := map[string]bool{url: true}
+	// matches counts the elements matched by each hook on the current page
+	matches := make([]int, len(allHooks))
+	for i, h := range allHooks {
+		func(i int, hook Hook) {
 			c.OnHTML(hook.DOMPath, func(e *colly.HTMLElement) {
 				mux.Lock()
+				defer mux.Unlock()
 
+				matches[i]++
 				logger.Debugf("DOMPath: %q, URL: %q", hook.DOMPath, e.Request.URL)
 				next, err := hook.Handler(e, data)
 				if err != nil {
-					logger.Errorf("error while handling dompath %q for request %q: %v", hook.DOMPath, e.Request.URL, err)
-					errs = append(errs, err)
+					var parseErr *ParseError
+					if errors.As(err, &parseErr) && len(parseErr.DOMPath) == 0 {
+						parseErr.DOMPath = hook.DOMPath
+					}
+					hookErr := &HookError{URL: e.Request.URL.String(), DOMPath: hook.DOMPath, Err: err}
+					logger.Error(hookErr)
+					errs = append(errs, hookErr)
 				}
-				mux.Unlock()
 
-				if next != nil {
-					c.Visit(*next)
+				if next != nil && !visited[*next] {
+					visited[*next] = true
+					queue = append(queue, *next)
 				}
 			})
-		}(h)
+		}(i, h)
 	}
+	// cacheStatus is the CacheStatusHeader of the latest response
+	cacheStatus := ""
+	c.OnResponse(func(r *colly.Response) {
+		cacheStatus = r.Headers.Get(CacheStatusHeader)
+	})
+	// statusCode is the status code of the latest failed response
+	statusCode := 0
+	c.OnError(func(r *colly.Response, _ error) {
+		statusCode = r.StatusCode
+	})
 	c.OnRequest(func(r *colly.Request) {
+		if ctx.Err() != nil {
+			r.Abort()
+			return
+		}
+		r.Ctx.Put(contextKey, ctx)
 		logger.Infof("%s visiting page %q", s.Name(), r.URL)
 	})
 
-	if err := c.Visit(url); err != nil {
+	for pages := 0; len(queue) > 0; pages++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if opts != nil && opts.MaxPages > 0 && pages >= opts.MaxPages {
+			logger.Infof("%s reached the limit of %d pages, not visiting %d more", s.Name(), opts.MaxPages, len(queue))
+			break
+		}
+
+		mux.Lock()
+		pageURL := queue[0]
+		queue = queue[1:]
+		mux.Unlock()
+
+		statusCode, cacheStatus = 0, ""
+		for i := range matches {
+			matches[i] = 0
+		}
+		if err := c.Visit(pageURL); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, &HTTPError{URL: pageURL, StatusCode: statusCode, Err: err}
+		}
+
+		report := newPageReport(s.Name(), pageURL, allHooks, matches)
+		report.Cache = cacheStatus
+		if len(cacheStatus) > 0 {
+			logger.Debugf("cache %s for page %q", cacheStatus, pageURL)
+		}
+		for _, h := range report.Hooks {
+			switch {
+			case h.OK():
+			case h.Required && h.Matches < h.MinMatches:
+				errs = append(errs, &NoMatchError{URL: pageURL, DOMPath: h.DOMPath})
+				logger.Errorf("required dompath %q matched %d elements on page %q, expected %s", h.DOMPath, h.Matches, pageURL, h.Expected())
+			default:
+				logger.Warnf("dompath %q matched %d elements on page %q, expected %s", h.DOMPath, h.Matches, pageURL, h.Expected())
+			}
+		}
+		for _, h := range report.Unmatched() {
+			logger.Debugf("dompath %q matched nothing on page %q", h.DOMPath, pageURL)
+		}
+		if opts != nil && opts.OnPage != nil {
+			opts.OnPage(report)
+		}
+	}
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
 	if len(errs) > 0 {
-		return nil, fmt.Errorf("errors occured during scraping: %v", errs)
+		return nil, Errors(errs)
 	}
 	return data, nil
 }
@@ -0,0 +1,220 @@
+/*
+The typed package contains a type-safe, generics-based API on top of the scraper package. Instead of
+sharing an interface{} between the hook handlers, which need to cast it to the real type, a
+Scraper[T] gets a *T passed to its handlers, and Scrape[T] returns the *T directly, e.g.
+
+	type MyScraper struct {}
+
+	func (ms *MyScraper) Name() string {
+		return "MyScraper"
+	}
+
+	func (ms *MyScraper) InitialData() *MyScrapedData {
+		return &MyScrapedData{}
+	}
+
+	func (ms *MyScraper) Hooks() []typed.Hook[MyScrapedData] {
+		return []typed.Hook[MyScrapedData]{
+			{
+				DOMPath: "#my-awesome-element",
+				Handler: extractImportantMessage,
+			},
+		}
+	}
+
+	func extractImportantMessage(e *colly.HTMLElement, data *MyScrapedData) (*string, error) {
+		data.ImportantMessage = e.Text
+		return nil, nil
+	}
+
+	func main() {
+		myData, err := typed.Scrape[MyScrapedData](context.Background(), "example.com", &MyScraper{}, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Important message is: %s", myData.ImportantMessage)
+	}
+
+The interface{}-based scraper.Scraper and scraper.Extension interfaces are still supported; Adapt and
+AdaptExtension convert typed implementations to them. Types implementing the untyped interfaces can
+expose their typed hooks using NewScraper and NewExtension.
+*/
+package typed
+
+import (
+	"context"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+)
+
+// HookFn is a callback function for processing HTML data at a given place in the DOM tree.
+// It works like scraper.HookFn, but the data argument is the *T returned by Scraper.InitialData().
+type HookFn[T any] func(e *colly.HTMLElement, data *T) (*string, error)
+
+// Hook maps a handler of type HookFn[T] to a DOMPath in the tree. See scraper.Hook for the meaning of the fields.
+type Hook[T any] struct {
+	// DOMPath specifies one or many elements in the DOM tree using a CSS selector
+	DOMPath string
+
+	// Handler specifies the handler to be invoked for all of the elements on the HTML page matched by the CSS selector
+	Handler HookFn[T]
+
+	// Required makes Scrape fail for pages where DOMPath matches less than MinMatches elements
+	Required bool
+
+	// MinMatches is the minimum amount of elements DOMPath is expected to match per page
+	MinMatches int
+
+	// MaxMatches is the maximum amount of elements DOMPath is expected to match per page, or zero if unlimited
+	MaxMatches int
+}
+
+// Untyped returns the scraper.Hook corresponding to h. The handler of the returned hook skips data
+// that is not a *T, instead of panicking.
+func (h Hook[T]) Untyped() scraper.Hook {
+	return scraper.Hook{
+		DOMPath: h.DOMPath,
+		Handler: func(e *colly.HTMLElement, data interface{}) (*string, error) {
+			t, ok := data.(*T)
+			if !ok {
+				return nil, nil
+			}
+			return h.Handler(e, t)
+		},
+		Required:   h.Required,
+		MinMatches: h.MinMatches,
+		MaxMatches: h.MaxMatches,
+	}
+}
+
+// Scraper is the typed equivalent of scraper.Scraper, scraping data into a *T
+type Scraper[T any] interface {
+	// Name returns an user-friendly name of the scraper
+	Name() string
+
+	// Hooks returns the hooks for all HTML elements that should be matched and their handlers.
+	Hooks() []Hook[T]
+
+	// InitialData returns the struct pointer which is then shared between/passed to all hook handlers.
+	InitialData() *T
+}
+
+// Extension is the typed equivalent of scraper.Extension, extending scrapers that scrape data into a *T
+type Extension[T any] interface {
+	// Name returns the name of the extension
+	Name() string
+
+	// Hook is the hook registered by this extension
+	Hook() Hook[T]
+}
+
+// NewScraper returns a Scraper[T] with the given name and hooks, whose InitialData is returned by
+// initialData
+func NewScraper[T any](name string, hooks []Hook[T], initialData func() *T) Scraper[T] {
+	return &scraperFunc[T]{name: name, hooks: hooks, initialData: initialData}
+}
+
+// NewExtension returns an Extension[T] with the given name and hook
+func NewExtension[T any](name string, hook Hook[T]) Extension[T] {
+	return &extensionFunc[T]{name: name, hook: hook}
+}
+
+// Scrape works like scraper.ScrapeContext, but returns the *T returned by s.InitialData() directly.
+// The typed extensions in exts are registered in addition to the ones in opts.Extensions.
+func Scrape[T any](ctx context.Context, url string, s Scraper[T], opts *scraper.ScrapeOptions, exts ...Extension[T]) (*T, error) {
+	o := scraper.ScrapeOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Extensions = append([]scraper.Extension{}, o.Extensions...)
+	for _, ext := range exts {
+		o.Extensions = append(o.Extensions, AdaptExtension(ext))
+	}
+
+	data, err := scraper.ScrapeContext(ctx, url, Adapt(s), &o)
+	if err != nil {
+		return nil, err
+	}
+	return data.(*T), nil
+}
+
+// Adapt returns a scraper.Scraper for the typed Scraper s
+func Adapt[T any](s Scraper[T]) scraper.Scraper {
+	return &adapter[T]{s}
+}
+
+// AdaptExtension returns a scraper.Extension for the typed Extension ext. The extension hook is only
+// invoked for scrapers scraping data into a *T, which makes it safe to pass the extension to scrapers
+// of other types too.
+func AdaptExtension[T any](ext Extension[T]) scraper.Extension {
+	return &extensionAdapter[T]{ext}
+}
+
+// adapter implements scraper.Scraper for a Scraper[T]
+type adapter[T any] struct {
+	s Scraper[T]
+}
+
+func (a *adapter[T]) Name() string {
+	return a.s.Name()
+}
+
+func (a *adapter[T]) Hooks() []scraper.Hook {
+	typedHooks := a.s.Hooks()
+	hooks := make([]scraper.Hook, 0, len(typedHooks))
+	for _, h := range typedHooks {
+		hooks = append(hooks, h.Untyped())
+	}
+	return hooks
+}
+
+func (a *adapter[T]) InitialData() interface{} {
+	return a.s.InitialData()
+}
+
+// extensionAdapter implements scraper.Extension for an Extension[T]
+type extensionAdapter[T any] struct {
+	ext Extension[T]
+}
+
+func (a *extensionAdapter[T]) Name() string {
+	return a.ext.Name()
+}
+
+func (a *extensionAdapter[T]) Hook() scraper.Hook {
+	return a.ext.Hook().Untyped()
+}
+
+// scraperFunc implements Scraper[T] for NewScraper
+type scraperFunc[T any] struct {
+	name        string
+	hooks       []Hook[T]
+	initialData func() *T
+}
+
+func (s *scraperFunc[T]) Name() string {
+	return s.name
+}
+
+func (s *scraperFunc[T]) Hooks() []Hook[T] {
+	return s.hooks
+}
+
+func (s *scraperFunc[T]) InitialData() *T {
+	return s.initialData()
+}
+
+// extensionFunc implements Extension[T] for NewExtension
+type extensionFunc[T any] struct {
+	name string
+	hook Hook[T]
+}
+
+func (e *extensionFunc[T]) Name() string {
+	return e.name
+}
+
+func (e *extensionFunc[T]) Hook() Hook[T] {
+	return e.hook
+}
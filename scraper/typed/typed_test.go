@@ -0,0 +1,187 @@
+package typed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+)
+
+const fixture = `<html><body><h1>Talks</h1><ul><li>One</li><li>Two</li></ul></body></html>`
+
+type page struct {
+	Title string
+	Items []string
+}
+
+type other struct {
+	Seen bool
+}
+
+func newFixtureServer(t *testing.T) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(fixture))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func pageScraper() Scraper[page] {
+	return NewScraper("PageScraper", []Hook[page]{
+		{
+			DOMPath:  "h1",
+			Required: true,
+			Handler: func(e *colly.HTMLElement, p *page) (*string, error) {
+				p.Title = e.Text
+				return nil, nil
+			},
+		},
+	}, func() *page { return &page{} })
+}
+
+func itemsExtension() Extension[page] {
+	return NewExtension("ItemsExtension", Hook[page]{
+		DOMPath: "li",
+		Handler: func(e *colly.HTMLElement, p *page) (*string, error) {
+			p.Items = append(p.Items, e.Text)
+			return nil, nil
+		},
+	})
+}
+
+func otherExtension() Extension[other] {
+	return NewExtension("OtherExtension", Hook[other]{
+		DOMPath: "li",
+		Handler: func(e *colly.HTMLElement, o *other) (*string, error) {
+			o.Seen = true
+			return nil, nil
+		},
+	})
+}
+
+func TestHookUntyped(t *testing.T) {
+	called := 0
+	h := Hook[page]{
+		DOMPath:    "h1",
+		Required:   true,
+		MinMatches: 1,
+		MaxMatches: 2,
+		Handler: func(e *colly.HTMLElement, p *page) (*string, error) {
+			called++
+			return nil, nil
+		},
+	}.Untyped()
+
+	if h.DOMPath != "h1" || !h.Required || h.MinMatches != 1 || h.MaxMatches != 2 {
+		t.Errorf("Untyped() = %+v, want the fields of the typed hook", h)
+	}
+	tests := []struct {
+		name       string
+		data       interface{}
+		wantCalled int
+	}{
+		{name: "*T", data: &page{}, wantCalled: 1},
+		{name: "T", data: page{}, wantCalled: 0},
+		{name: "other type", data: &other{}, wantCalled: 0},
+		{name: "nil", data: nil, wantCalled: 0},
+	}
+	for _, tt := range tests {
+		called = 0
+		if _, err := h.Handler(nil, tt.data); err != nil {
+			t.Errorf("%s: handler error = %v", tt.name, err)
+		}
+		if called != tt.wantCalled {
+			t.Errorf("%s: handler called %d times, want %d", tt.name, called, tt.wantCalled)
+		}
+	}
+}
+
+func TestScrape(t *testing.T) {
+	s := newFixtureServer(t)
+	tests := []struct {
+		name      string
+		opts      *scraper.ScrapeOptions
+		exts      []Extension[page]
+		wantPage  page
+		wantOther bool
+	}{
+		{
+			name:     "no options",
+			wantPage: page{Title: "Talks"},
+		},
+		{
+			name:     "typed extension",
+			exts:     []Extension[page]{itemsExtension()},
+			wantPage: page{Title: "Talks", Items: []string{"One", "Two"}},
+		},
+		{
+			name:     "adapted extension in options",
+			opts:     &scraper.ScrapeOptions{Extensions: []scraper.Extension{AdaptExtension(itemsExtension())}},
+			wantPage: page{Title: "Talks", Items: []string{"One", "Two"}},
+		},
+		{
+			name:     "extension of another type",
+			opts:     &scraper.ScrapeOptions{Extensions: []scraper.Extension{AdaptExtension(otherExtension())}},
+			wantPage: page{Title: "Talks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Scrape(context.Background(), s.URL, pageScraper(), tt.opts, tt.exts...)
+			if err != nil {
+				t.Fatalf("Scrape() error = %v", err)
+			}
+			if !reflect.DeepEqual(*p, tt.wantPage) {
+				t.Errorf("Scrape() = %+v, want %+v", *p, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestScrapeKeepsOptions(t *testing.T) {
+	s := newFixtureServer(t)
+	ext := AdaptExtension(otherExtension())
+	// Leave room in the slice, so that appending to it would write into the array of the caller
+	extensions := make([]scraper.Extension, 1, 4)
+	extensions[0] = ext
+	opts := &scraper.ScrapeOptions{Extensions: extensions}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Scrape(context.Background(), s.URL, pageScraper(), opts, itemsExtension()); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+	}
+	if len(opts.Extensions) != 1 || opts.Extensions[0] != ext {
+		t.Errorf("opts.Extensions = %v, want only the original extension", opts.Extensions)
+	}
+	if extra := extensions[:2][1]; extra != nil {
+		t.Errorf("Scrape() appended %v into the array of opts.Extensions", extra)
+	}
+}
+
+func TestAdapt(t *testing.T) {
+	s := newFixtureServer(t)
+	untyped := Adapt(pageScraper())
+	if untyped.Name() != "PageScraper" || len(untyped.Hooks()) != 1 {
+		t.Errorf("Adapt() = %s with %d hooks, want PageScraper with 1 hook", untyped.Name(), len(untyped.Hooks()))
+	}
+
+	data, err := scraper.Scrape(s.URL, untyped, &scraper.ScrapeOptions{
+		Extensions: []scraper.Extension{AdaptExtension(itemsExtension()), AdaptExtension(otherExtension())},
+	})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	p, ok := data.(*page)
+	if !ok {
+		t.Fatalf("Scrape() returned a %T, want a *page", data)
+	}
+	if want := (page{Title: "Talks", Items: []string{"One", "Two"}}); !reflect.DeepEqual(*p, want) {
+		t.Errorf("Scrape() = %+v, want %+v", *p, want)
+	}
+}
@@ -0,0 +1,151 @@
+package speakerdeck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
+)
+
+// DefaultSearchMaxPages is how many pages of search results are scraped, unless opts.MaxPages is set
+const DefaultSearchMaxPages = 5
+
+// ScrapeSearch returns the talks found when searching Speakerdeck for the given query.
+// ScrapeSearch uses DefaultClient, see Client.ScrapeSearch.
+func ScrapeSearch(query string, opts *scraper.ScrapeOptions) (*SearchResults, error) {
+	return DefaultClient.ScrapeSearch(query, opts)
+}
+
+// ScrapeSearchContext works like ScrapeSearch, but stops scraping when ctx is done.
+// ScrapeSearchContext uses DefaultClient, see Client.ScrapeSearchContext.
+func ScrapeSearchContext(ctx context.Context, query string, opts *scraper.ScrapeOptions) (*SearchResults, error) {
+	return DefaultClient.ScrapeSearchContext(ctx, query, opts)
+}
+
+// StreamSearch works like ScrapeSearchContext, but calls fn for each talk as soon as it is found.
+// StreamSearch uses DefaultClient, see Client.StreamSearch.
+func StreamSearch(ctx context.Context, query string, opts *scraper.ScrapeOptions, fn func(AuthoredTalkPreview) error) error {
+	return DefaultClient.StreamSearch(ctx, query, opts, fn)
+}
+
+// ScrapeSearch returns the talks found when searching Speakerdeck for the given query.
+// At most opts.MaxPages pages of results are scraped, or DefaultSearchMaxPages if not set.
+func (c *Client) ScrapeSearch(query string, opts *scraper.ScrapeOptions) (*SearchResults, error) {
+	return c.ScrapeSearchContext(context.Background(), query, opts)
+}
+
+// ScrapeSearchContext works like ScrapeSearch, but stops scraping when ctx is done. No further
+// pages of results are visited after ctx is cancelled.
+func (c *Client) ScrapeSearchContext(ctx context.Context, query string, opts *scraper.ScrapeOptions) (*SearchResults, error) {
+	return c.scrapeSearch(ctx, query, opts, &SearchScraper{})
+}
+
+// StreamSearch works like ScrapeSearchContext, but calls fn for each talk as soon as it is found,
+// instead of returning all talks once every page has been scraped. If fn returns an error, no
+// further pages are visited and that error is returned.
+func (c *Client) StreamSearch(ctx context.Context, query string, opts *scraper.ScrapeOptions, fn func(AuthoredTalkPreview) error) error {
+	// stop cancels the scrape if fn fails
+	streamCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var fnErr error
+	s := &SearchScraper{
+		OnTalkFound: func(t AuthoredTalkPreview) {
+			if fnErr != nil {
+				return
+			}
+			if fnErr = fn(t); fnErr != nil {
+				stop()
+			}
+		},
+	}
+	_, err := c.scrapeSearch(streamCtx, query, opts, s)
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+func (c *Client) scrapeSearch(ctx context.Context, query string, opts *scraper.ScrapeOptions, s *SearchScraper) (*SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if len(query) == 0 {
+		return nil, fmt.Errorf("query is mandatory!")
+	}
+
+	opts = c.scrapeOptions(opts)
+	if opts.MaxPages == 0 {
+		opts.MaxPages = DefaultSearchMaxPages
+	}
+	searchURL := c.url("search") + "?" + url.Values{"q": {query}}.Encode()
+	results, err := typed.Scrape[SearchResults](ctx, searchURL, s.Typed(), opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not search for %q: %w", query, err)
+	}
+	results.Query = query
+	results.Link = searchURL
+	return results, nil
+}
+
+var _ scraper.Scraper = &SearchScraper{}
+
+// SearchScraper implements scraper.Scraper. Its typed.Scraper[SearchResults] is returned by Typed
+type SearchScraper struct {
+	// OnTalkFound is called for each talk as soon as it is found, if set
+	OnTalkFound func(AuthoredTalkPreview)
+}
+
+// Name returns the name of the SearchScraper
+func (s *SearchScraper) Name() string {
+	return "SearchScraper"
+}
+
+// Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
+// of them
+func (s *SearchScraper) Hooks() []scraper.Hook {
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *SearchScraper) InitialData() interface{} {
+	return NewSearchResults()
+}
+
+// Typed returns the SearchScraper as a typed.Scraper[SearchResults], whose handlers get the *SearchResults
+// passed directly
+func (s *SearchScraper) Typed() typed.Scraper[SearchResults] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewSearchResults)
+}
+
+func (s *SearchScraper) typedHooks() []typed.Hook[SearchResults] {
+	return []typed.Hook[SearchResults]{
+		{
+			DOMPath: ".container .row > div:has(a[href][title])",
+			Handler: s.onSearchTalkFound,
+		},
+		{
+			DOMPath:    ".next .page-link[rel='next']",
+			Handler:    onNextPage[SearchResults],
+			MaxMatches: 1,
+		},
+	}
+}
+
+func (s *SearchScraper) onSearchTalkFound(e *colly.HTMLElement, r *SearchResults) (*string, error) {
+	t, err := parseAuthoredTalkPreview(e)
+	if err != nil {
+		return nil, err
+	}
+	r.TalkPreviews = append(r.TalkPreviews, *t)
+	if s.OnTalkFound != nil {
+		s.OnTalkFound(*t)
+	}
+	return nil, nil
+}
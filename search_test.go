@@ -0,0 +1,160 @@
+package speakerdeck_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+// searchTalks returns the talks of users whose title or description contain query, ignoring case,
+// newest first
+func searchTalks(users []speakerdecktest.User, query string) []listedTalk {
+	return listedTalks(users, func(t speakerdecktest.Talk) bool {
+		return strings.Contains(strings.ToLower(t.Title+"\n"+t.Description), strings.ToLower(query))
+	})
+}
+
+func TestScrapeSearch(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	tests := []struct {
+		name     string
+		query    string
+		maxPages int
+		// wantTalks is how many of the matching talks are expected, newest first
+		wantTalks int
+		wantPages int
+		wantQuery string
+	}{
+		{
+			name:      "paginated results",
+			query:     "kubernetes",
+			wantTalks: 2,
+			wantPages: 2,
+			wantQuery: "kubernetes",
+		},
+		{
+			name:      "MaxPages",
+			query:     "kubernetes",
+			maxPages:  1,
+			wantTalks: 1,
+			wantPages: 1,
+			wantQuery: "kubernetes",
+		},
+		{
+			name:      "surrounding whitespace",
+			query:     "  ignite ",
+			wantTalks: 1,
+			wantPages: 1,
+			wantQuery: "ignite",
+		},
+		{
+			name:      "no results",
+			query:     "cobol",
+			wantTalks: 0,
+			wantPages: 1,
+			wantQuery: "cobol",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			// Paginate the few matching fixture talks
+			s.PageSize = 1
+
+			results, err := s.NewClient().ScrapeSearch(tt.query, &scraper.ScrapeOptions{MaxPages: tt.maxPages})
+			if err != nil {
+				t.Fatalf("ScrapeSearch() error = %v", err)
+			}
+			if got := s.Requests("/search"); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			link := s.URL + "/search?" + url.Values{"q": {tt.wantQuery}}.Encode()
+			if results.Query != tt.wantQuery || results.Link != link {
+				t.Errorf("results for %q (%q), want %q (%q)", results.Query, results.Link, tt.wantQuery, link)
+			}
+			want := searchTalks(users, tt.wantQuery)
+			if len(want) < tt.wantTalks {
+				t.Fatalf("the fixtures have only %d talks matching %q", len(want), tt.wantQuery)
+			}
+			checkAuthoredTalkPreviews(t, s.URL, results.TalkPreviews, want[:tt.wantTalks])
+		})
+	}
+
+	if _, err := speakerdeck.ScrapeSearch(" ", nil); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestStreamSearch(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	errStop := errors.New("stop")
+	tests := []struct {
+		name string
+		// fail makes the callback fail for the first talk
+		fail bool
+		// cancelAfter cancels the context after this many talks, if positive
+		cancelAfter int
+		wantTalks   int
+		wantPages   int
+		wantErr     error
+	}{
+		{
+			name:      "all talks",
+			wantTalks: 2,
+			wantPages: 2,
+		},
+		{
+			name:      "callback error stops the search",
+			fail:      true,
+			wantTalks: 0,
+			wantPages: 1,
+			wantErr:   errStop,
+		},
+		{
+			name:        "cancelled",
+			cancelAfter: 1,
+			wantTalks:   1,
+			wantPages:   1,
+			wantErr:     context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			// Paginate the few matching fixture talks
+			s.PageSize = 1
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			talks := []speakerdeck.AuthoredTalkPreview{}
+			err := s.NewClient().StreamSearch(ctx, "kubernetes", nil, func(preview speakerdeck.AuthoredTalkPreview) error {
+				if tt.fail {
+					return errStop
+				}
+				talks = append(talks, preview)
+				if len(talks) == tt.cancelAfter {
+					cancel()
+				}
+				return nil
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("StreamSearch() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StreamSearch() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := s.Requests("/search"); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			checkAuthoredTalkPreviews(t, s.URL, talks, searchTalks(users, "kubernetes")[:tt.wantTalks])
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package speakerdeck
+
+import (
+	"strings"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
+)
+
+var _ scraper.Extension = &SlidesExtension{}
+
+// SlidesExtension implements scraper.Extension, and makes the TalkScraper scrape the slides of talks into
+// Talk.Slides. Slides are opt-in, as the transcript of a long presentation makes up most of the talk.
+// Its typed.Extension[Talk] is returned by Typed.
+type SlidesExtension struct{}
+
+// Name returns the SlidesExtension name
+func (_ *SlidesExtension) Name() string {
+	return "SlidesExtension"
+}
+
+// Hook returns the hook for this extension. It matches the whole talk page once, as the thumbnail
+// of the presentation is not part of the transcript.
+func (se *SlidesExtension) Hook() scraper.Hook {
+	return typed.AdaptExtension(se.Typed()).Hook()
+}
+
+// Typed returns the SlidesExtension as a typed.Extension[Talk]
+func (se *SlidesExtension) Typed() typed.Extension[Talk] {
+	return typed.NewExtension(se.Name(), typed.Hook[Talk]{
+		DOMPath:    "html",
+		Handler:    onTalkSlides,
+		MaxMatches: 1,
+	})
+}
+
+// onTalkSlides registers the thumbnail and the slides listed in the transcript of the talk
+func onTalkSlides(e *colly.HTMLElement, t *Talk) (*string, error) {
+	t.Slides = &Slides{
+		Pages: []Slide{},
+	}
+	if thumbnail := e.ChildAttr("meta[itemprop='thumbnailUrl']", "content"); len(thumbnail) > 0 {
+		t.Slides.ThumbnailLink = e.Request.AbsoluteURL(thumbnail)
+	}
+
+	var err error
+	e.ForEach(".deck-transcript li", func(_ int, li *colly.HTMLElement) {
+		if err == nil {
+			err = onTalkSlide(li, t.Slides)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Slides.Count = len(t.Slides.Pages)
+	return nil, nil
+}
+
+// onTalkSlide adds the slide of the transcript list item e to slides
+func onTalkSlide(e *colly.HTMLElement, slides *Slides) error {
+	srcset := e.ChildAttr("img", "srcset")
+	images, err := parseSrcset(srcset, e.Request.AbsoluteURL)
+	if err != nil {
+		return &scraper.ParseError{Text: srcset, Err: err}
+	}
+
+	slides.Pages = append(slides.Pages, Slide{
+		Number:    len(slides.Pages) + 1,
+		Link:      e.Request.AbsoluteURL(e.ChildAttr("a", "href")),
+		ImageLink: e.Request.AbsoluteURL(e.ChildAttr("img", "src")),
+		Images:    images,
+		Text:      strings.TrimSpace(e.ChildText("p")),
+	})
+	return nil
+}
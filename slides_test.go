@@ -0,0 +1,67 @@
+package speakerdeck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	absoluteURL := func(link string) string {
+		return "https://files.speakerdeck.com" + link
+	}
+	tests := []struct {
+		name    string
+		srcset  string
+		want    []SlideImage
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			srcset: "",
+			want:   []SlideImage{},
+		},
+		{
+			name:   "ordered by width",
+			srcset: "/slide_0_2048.jpg 2048w, /slide_0_1024.jpg 1024w",
+			want: []SlideImage{
+				{Width: 1024, Link: "https://files.speakerdeck.com/slide_0_1024.jpg"},
+				{Width: 2048, Link: "https://files.speakerdeck.com/slide_0_2048.jpg"},
+			},
+		},
+		{
+			name:   "pixel densities are skipped",
+			srcset: "/slide_0_1024.jpg 1024w, /slide_0_2x.jpg 2x",
+			want: []SlideImage{
+				{Width: 1024, Link: "https://files.speakerdeck.com/slide_0_1024.jpg"},
+			},
+		},
+		{
+			name:   "trailing comma",
+			srcset: "/slide_0_1024.jpg 1024w,",
+			want: []SlideImage{
+				{Width: 1024, Link: "https://files.speakerdeck.com/slide_0_1024.jpg"},
+			},
+		},
+		{
+			name:    "missing descriptor",
+			srcset:  "/slide_0_1024.jpg",
+			wantErr: true,
+		},
+		{
+			name:    "invalid width",
+			srcset:  "/slide_0_1024.jpg bigw",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSrcset(tt.srcset, absoluteURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSrcset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSrcset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package speakerdecktest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// User describes a fixture user, served at /{handle}
+type User struct {
+	// Name describes the human-friendly name of the user
+	Name string
+
+	// Handle describes the nickname of the user, used in the URL
+	Handle string
+
+	// AvatarLink is the protocol-relative link to the avatar of the user, e.g. "//secure.gravatar.com/avatar/..."
+	AvatarLink string
+
+	// Abstract contains a short description of the user
+	Abstract string
+
+	// Talks contains the talks of the user, in the order they are listed on the user page
+	Talks []Talk
+
+	// Stars contains the talks the user has starred, as "{handle}/{talk-id}" references, in the order
+	// they are listed on the stars page at /{handle}/stars
+	Stars []string
+
+	// Following contains the handles of the users this user follows, in the order they are listed on
+	// /{handle}/following. The followers listed on /{handle}/followers are derived from this field
+	Following []string
+}
+
+// Talk describes a fixture talk, served at /{handle}/{id}
+type Talk struct {
+	// ID is the URL-encoded descriptor for the talk
+	ID string
+
+	// Title describes the talk title
+	Title string
+
+	// DataID represents the key used to embed the presentation
+	DataID string
+
+	// Date represents the talk presentation date
+	Date time.Time
+
+	// Category is the Speakerdeck category of the talk, e.g. "Technology". The category page
+	// is served at /c/{category}, where {category} is the lowercased, dash-separated category
+	Category string
+
+	// Stars describes how many users have starred this talk
+	Stars uint32
+
+	// Views describes how many views a talk has got
+	Views uint32
+
+	// Description is the talk description. Paragraphs are separated by blank lines, and
+	// links in the text are rendered as anchors, as Speakerdeck does.
+	Description string
+
+	// DownloadLink is the link to the underlying PDF
+	DownloadLink string
+
+	// Slides contains the transcript text of each slide. The slide images are served from
+	// SlideImageLink, at the widths listed in SlideImageWidths
+	Slides []string
+}
+
+// DefaultUsers returns the fixture users served by NewServer if no other users are given.
+// The first user has more talks than fit on one page, so its user page is paginated, and the
+// last user has no talks at all, but follows the others.
+func DefaultUsers() []User {
+	return []User{
+		{
+			Name:       "Lucas Käldström",
+			Handle:     "luxas",
+			AvatarLink: "//secure.gravatar.com/avatar/111ac0b31c0dc219c84ddadedc8e5f67?s=128",
+			Abstract:   "Lucas is a cloud native enthusiast who has been serving the Kubernetes & CNCF communities in lead positions.",
+			Stars:      []string{"janedoe/consensus-for-humans"},
+			Following:  []string{"janedoe"},
+			Talks: []Talk{
+				{
+					ID:           "getting-started-in-the-kubernetes-community",
+					Title:        "Getting Started in the Kubernetes Community",
+					DataID:       "6816e10f104a44cebb0915b392cadd2d",
+					Date:         date(2019, time.May, 21),
+					Category:     "Technology",
+					Stars:        1,
+					Views:        92,
+					Description:  "Contributor summit talk together with Nikhita Raghunath https://github.com/nikhita\n\nEvent: KubeCon + CloudNativeCon Europe 2019\nCo-speakers: Nikhita Raghunath\nLocation: Fira Gran Via, Av. Joan Carles I, Barcelona, Spain",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/6816e10f104a44cebb0915b392cadd2d/getting-started.pdf",
+					Slides: []string{
+						"Getting Started in the Kubernetes Community",
+						"Who are we? Lucas Käldström & Nikhita Raghunath",
+						"How to find your first issue: look for good-first-issue labels",
+					},
+				},
+				{
+					ID:           "kubeadm-cluster-creation-internals",
+					Title:        "kubeadm Cluster Creation Internals",
+					DataID:       "3a8d5fd3c5a34a3d8b1a7b9a7e4c1a11",
+					Date:         date(2018, time.May, 3),
+					Category:     "Technology",
+					Stars:        12,
+					Views:        1500,
+					Description:  "Deep dive into kubeadm. Recording: https://www.youtube.com/watch?v=GMdT5l-3lAQ\n\nLocation: Bella Center, Copenhagen",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/3a8d5fd3c5a34a3d8b1a7b9a7e4c1a11/kubeadm.pdf",
+					Slides: []string{
+						"kubeadm Cluster Creation Internals",
+						"kubeadm init phases: preflight, certs, kubeconfig, control-plane",
+					},
+				},
+				{
+					ID:           "cloud-native-nordics-meetup",
+					Title:        "Cloud Native Nordics Meetup",
+					DataID:       "9f1c2b3a4d5e4f60718293a4b5c6d7e8",
+					Date:         date(2020, time.February, 12),
+					Category:     "Technology",
+					Stars:        3,
+					Views:        240,
+					Description:  "Welcome slides for the meetup. Recording: https://vimeo.com/channels/cloudnative/123456789#t=1m30s\n\nLocation: Online",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/9f1c2b3a4d5e4f60718293a4b5c6d7e8/meetup.pdf",
+				},
+				{
+					ID:           "raspberry-pi-kubernetes",
+					Title:        "Kubernetes on Raspberry Pi",
+					DataID:       "0c1d2e3f405142638495a6b7c8d9e0f1",
+					Date:         date(2016, time.November, 8),
+					Category:     "Programming",
+					Stars:        7,
+					Views:        3200,
+					Description:  "An old talk.\n\nHide: true",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/0c1d2e3f405142638495a6b7c8d9e0f1/rpi.pdf",
+				},
+				{
+					ID:           "weave-ignite",
+					Title:        "Weave Ignite: Firecracker microVMs with a Docker UX",
+					DataID:       "5e6f708192a34b5c6d7e8f9012345678",
+					Date:         date(2019, time.August, 14),
+					Category:     "Technology",
+					Stars:        5,
+					Views:        610,
+					Description:  "Code at https://github.com/weaveworks/ignite (docs at https://ignite.readthedocs.io/en/stable/?utm_source=speakerdeck&utm_medium=talk).\n\nVideo: https://youtu.be/aqcQhQ3R7dI?t=90",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/5e6f708192a34b5c6d7e8f9012345678/ignite.pdf",
+					Slides: []string{
+						"Weave Ignite",
+					},
+				},
+			},
+		},
+		{
+			Name:       "Jane Doe",
+			Handle:     "janedoe",
+			AvatarLink: "//secure.gravatar.com/avatar/0123456789abcdef0123456789abcdef?s=128",
+			Abstract:   "Jane speaks about distributed systems.",
+			Stars: []string{
+				"luxas/weave-ignite",
+				"luxas/getting-started-in-the-kubernetes-community",
+				"luxas/kubeadm-cluster-creation-internals",
+			},
+			Following: []string{"luxas"},
+			Talks: []Talk{
+				{
+					ID:           "consensus-for-humans",
+					Title:        "Consensus for Humans",
+					DataID:       "abcdef0123456789abcdef0123456789",
+					Date:         date(2019, time.October, 1),
+					Category:     "Technology",
+					Stars:        1200,
+					Views:        45000,
+					Description:  "An introduction to Raft.",
+					DownloadLink: "https://speakerd.s3.amazonaws.com/presentations/abcdef0123456789abcdef0123456789/consensus.pdf",
+				},
+			},
+		},
+		{
+			Name:       "Kube Fan",
+			Handle:     "kubefan",
+			AvatarLink: "//secure.gravatar.com/avatar/fedcba9876543210fedcba9876543210?s=128",
+			Abstract:   "Watches all the Kubernetes talks.",
+			Following:  []string{"luxas", "janedoe"},
+		},
+	}
+}
+
+// SlideImageWidths are the widths in pixels of the slide images rendered on the talk pages
+var SlideImageWidths = []int{1024, 2048}
+
+// SlideImageLink returns the link to the image of the zero-based slide of the presentation with the
+// given DataID, at the given width
+func SlideImageLink(dataID string, slide, width int) string {
+	return fmt.Sprintf("https://files.speakerdeck.com/presentations/%s/slide_%d_%d.jpg", dataID, slide, width)
+}
+
+// ThumbnailLink returns the link to the thumbnail of the presentation with the given DataID
+func ThumbnailLink(dataID string) string {
+	return fmt.Sprintf("https://files.speakerdeck.com/presentations/%s/thumb_slide_0.jpg", dataID)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// categorySlug returns the URL-encoded form of a category, e.g. "technology" for "Technology"
+func categorySlug(category string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "-")
+}
@@ -0,0 +1,392 @@
+/*
+The speakerdecktest package contains a fake, offline Speakerdeck server for use in tests. The server
+renders user pages (paginated), talk pages, stars, followers and following pages, category pages, search
+results and error pages from fixture data, using the same markup as speakerdeck.com, so that ScrapeUser
+and ScrapeTalks can be exercised without any network access, e.g.
+
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+
+	user, err := s.NewClient().ScrapeUser("luxas", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(user.TalkPreviews) != len(speakerdecktest.DefaultUsers()[0].Talks) {
+		t.Errorf("unexpected amount of talks: %d", len(user.TalkPreviews))
+	}
+*/
+package speakerdecktest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	speakerdeck "github.com/luxas/speakerdeck-api"
+)
+
+// DefaultPageSize is the default amount of talks listed per page on user and category pages
+const DefaultPageSize = 2
+
+// NewServer starts a new fake Speakerdeck server serving the given users. If no users
+// are given, DefaultUsers() is used. The caller should call Close when finished, to shut it down.
+func NewServer(users ...User) *Server {
+	if len(users) == 0 {
+		users = DefaultUsers()
+	}
+
+	s := &Server{
+		PageSize: DefaultPageSize,
+		users:    map[string]*User{},
+		failures: map[string][]int{},
+		requests: map[string]int{},
+	}
+	for i := range users {
+		s.users[strings.ToLower(users[i].Handle)] = &users[i]
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Server is a fake Speakerdeck server, serving fixture data over HTTP from an httptest.Server
+type Server struct {
+	*httptest.Server
+
+	// PageSize specifies how many talks are listed per page on user and category pages
+	PageSize int
+
+	mux      sync.Mutex
+	users    map[string]*User
+	failures map[string][]int
+	requests map[string]int
+}
+
+// NewClient returns a speakerdeck.Client scraping this server
+func (s *Server) NewClient() *speakerdeck.Client {
+	return &speakerdeck.Client{
+		BaseURL:   s.URL,
+		Transport: s.Client().Transport,
+	}
+}
+
+// FailNext makes the next requests to path (e.g. "/luxas") fail with the given HTTP status codes, in order.
+// After all status codes have been served, path is served normally again.
+func (s *Server) FailNext(path string, statusCodes ...int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failures[path] = append(s.failures[path], statusCodes...)
+}
+
+// Requests returns how many requests have been made to path (e.g. "/luxas"), including failed ones
+func (s *Server) Requests(path string) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.requests[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	s.requests[r.URL.Path]++
+	var failure int
+	if codes := s.failures[r.URL.Path]; len(codes) > 0 {
+		failure, s.failures[r.URL.Path] = codes[0], codes[1:]
+	}
+	s.mux.Unlock()
+
+	if failure != 0 {
+		serveError(w, failure)
+		return
+	}
+
+	if r.URL.Path == "/robots.txt" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("User-agent: *\nAllow: /\n"))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	page := pageNumber(r)
+	switch {
+	case len(parts) == 1 && parts[0] == "search":
+		s.serveSearch(w, r, r.URL.Query().Get("q"), page)
+	case len(parts) == 2 && parts[0] == "c":
+		s.serveCategory(w, r, parts[1], page)
+	case len(parts) == 1 && len(parts[0]) > 0:
+		s.serveUser(w, r, parts[0], page)
+	case len(parts) == 2 && (parts[1] == "followers" || parts[1] == "following"):
+		s.serveConnections(w, r, parts[0], parts[1], page)
+	case len(parts) == 2 && parts[1] == "stars":
+		s.serveStars(w, r, parts[0], page)
+	case len(parts) == 2:
+		s.serveTalk(w, r, parts[0], parts[1])
+	default:
+		serveError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request, handle string, page int) {
+	u, ok := s.user(handle)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+
+	talks := make([]listedTalk, 0, len(u.Talks))
+	for i := range u.Talks {
+		talks = append(talks, listedTalk{User: u, Talk: &u.Talks[i]})
+	}
+	listing, ok := s.paginate(talks, page, "/"+handle)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+
+	render(w, r, userTemplate, &userPage{User: u, Listing: listing})
+}
+
+func (s *Server) serveTalk(w http.ResponseWriter, r *http.Request, handle, talkID string) {
+	u, ok := s.user(handle)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	for i := range u.Talks {
+		if u.Talks[i].ID == talkID {
+			render(w, r, talkTemplate, &listedTalk{User: u, Talk: &u.Talks[i]})
+			return
+		}
+	}
+	serveError(w, http.StatusNotFound)
+}
+
+func (s *Server) serveStars(w http.ResponseWriter, r *http.Request, handle string, page int) {
+	u, ok := s.user(handle)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+
+	talks := make([]listedTalk, 0, len(u.Stars))
+	for _, ref := range u.Stars {
+		if t, ok := s.lookupTalk(ref); ok {
+			talks = append(talks, t)
+		}
+	}
+	listing, ok := s.paginate(talks, page, "/"+handle+"/stars")
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	render(w, r, starsTemplate, &userPage{User: u, Listing: listing})
+}
+
+// serveConnections lists the followers of the user, or the users the user is following
+func (s *Server) serveConnections(w http.ResponseWriter, r *http.Request, handle, list string, page int) {
+	u, ok := s.user(handle)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+
+	users := []*User{}
+	if list == "following" {
+		for _, h := range u.Following {
+			if followed, ok := s.user(h); ok {
+				users = append(users, followed)
+			}
+		}
+	} else {
+		for _, follower := range s.sortedUsers() {
+			for _, h := range follower.Following {
+				if strings.EqualFold(h, handle) {
+					users = append(users, follower)
+				}
+			}
+		}
+	}
+
+	start, end, ok := s.pageBounds(len(users), page)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	p := &connectionsPage{User: u, Users: users[start:end]}
+	if end < len(users) {
+		p.NextLink = nextPageLink("/"+handle+"/"+list, page)
+	}
+	render(w, r, connectionsTemplate, p)
+}
+
+// user returns the user with the given handle. Like on speakerdeck.com, handles are
+// matched case-insensitively
+func (s *Server) user(handle string) (*User, bool) {
+	u, ok := s.users[strings.ToLower(handle)]
+	return u, ok
+}
+
+// lookupTalk returns the talk referenced as "{handle}/{talk-id}", if it exists
+func (s *Server) lookupTalk(ref string) (listedTalk, bool) {
+	handle, talkID, _ := strings.Cut(ref, "/")
+	u, ok := s.user(handle)
+	if !ok {
+		return listedTalk{}, false
+	}
+	for i := range u.Talks {
+		if u.Talks[i].ID == talkID {
+			return listedTalk{User: u, Talk: &u.Talks[i], ShowAuthor: true}, true
+		}
+	}
+	return listedTalk{}, false
+}
+
+func (s *Server) serveCategory(w http.ResponseWriter, r *http.Request, slug string, page int) {
+	name := ""
+	talks := []listedTalk{}
+	for _, u := range s.sortedUsers() {
+		for i := range u.Talks {
+			if categorySlug(u.Talks[i].Category) == slug {
+				name = u.Talks[i].Category
+				talks = append(talks, listedTalk{User: u, Talk: &u.Talks[i], ShowAuthor: true})
+			}
+		}
+	}
+	if len(talks) == 0 {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	// Newest talks are listed first
+	sort.SliceStable(talks, func(i, j int) bool {
+		return talks[i].Talk.Date.After(talks[j].Talk.Date)
+	})
+
+	listing, ok := s.paginate(talks, page, "/c/"+slug)
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	render(w, r, categoryTemplate, &categoryPage{Name: name, Listing: listing})
+}
+
+// serveSearch lists the talks whose title or description contain query, ignoring case
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request, query string, page int) {
+	talks := []listedTalk{}
+	needle := strings.ToLower(strings.TrimSpace(query))
+	for _, u := range s.sortedUsers() {
+		for i := range u.Talks {
+			t := &u.Talks[i]
+			if len(needle) > 0 && strings.Contains(strings.ToLower(t.Title+"\n"+t.Description), needle) {
+				talks = append(talks, listedTalk{User: u, Talk: t, ShowAuthor: true})
+			}
+		}
+	}
+	// Newest talks are listed first
+	sort.SliceStable(talks, func(i, j int) bool {
+		return talks[i].Talk.Date.After(talks[j].Talk.Date)
+	})
+
+	listing, ok := s.paginate(talks, page, "/search?"+url.Values{"q": {query}}.Encode())
+	if !ok {
+		serveError(w, http.StatusNotFound)
+		return
+	}
+	render(w, r, searchTemplate, &searchPage{Query: query, Listing: listing})
+}
+
+// paginate returns the talks for the given page, and a link to the next page if there is one.
+// false is returned if page is out of range.
+func (s *Server) paginate(talks []listedTalk, page int, path string) (*listing, bool) {
+	start, end, ok := s.pageBounds(len(talks), page)
+	if !ok {
+		return nil, false
+	}
+	l := &listing{Talks: talks[start:end]}
+	if end < len(talks) {
+		l.NextLink = nextPageLink(path, page)
+	}
+	return l, true
+}
+
+// pageBounds returns the range of the n listed items shown on the given page. false is returned
+// if page is out of range.
+func (s *Server) pageBounds(n, page int) (int, int, bool) {
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = n + 1
+	}
+
+	start := (page - 1) * pageSize
+	if page < 1 || (start >= n && page != 1) {
+		return 0, 0, false
+	}
+	end := start + pageSize
+	if end > n {
+		end = n
+	}
+	return start, end, true
+}
+
+// nextPageLink returns the link to the page after the given one of the listing at path
+func nextPageLink(path string, page int) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "page=" + strconv.Itoa(page+1)
+}
+
+func (s *Server) sortedUsers() []*User {
+	users := make([]*User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Handle < users[j].Handle
+	})
+	return users
+}
+
+func pageNumber(r *http.Request) int {
+	pageStr := r.URL.Query().Get("page")
+	if len(pageStr) == 0 {
+		return 1
+	}
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		return 0
+	}
+	return page
+}
+
+func serveError(w http.ResponseWriter, statusCode int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	errorTemplate.Execute(w, &errorPage{StatusCode: statusCode, Status: http.StatusText(statusCode)})
+}
+
+// render renders the page, and sets an ETag computed from its content. Conditional
+// requests with a matching If-None-Match header get a 304 Not Modified response.
+func render(w http.ResponseWriter, r *http.Request, tmpl *template.Template, data interface{}) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hash := sha256.Sum256(buf.Bytes())
+	etag := `W/"` + hex.EncodeToString(hash[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
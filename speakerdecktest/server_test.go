@@ -0,0 +1,175 @@
+package speakerdecktest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "first user page",
+			path:         "/luxas",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"Lucas Käldström", "/luxas?page=2"},
+		},
+		{
+			name:         "last user page",
+			path:         "/luxas?page=3",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"/luxas/weave-ignite"},
+			wantMissing:  []string{"/luxas?page=4"},
+		},
+		{
+			name:       "page out of range",
+			path:       "/luxas?page=4",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid page",
+			path:       "/luxas?page=first",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:        "user without talks",
+			path:        "/kubefan",
+			wantStatus:  http.StatusOK,
+			wantMissing: []string{"?page=2"},
+		},
+		{
+			name:       "unknown user",
+			path:       "/nobody",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "talk",
+			path:         "/luxas/weave-ignite",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"https://github.com/weaveworks/ignite"},
+		},
+		{
+			name:       "unknown talk",
+			path:       "/luxas/nothing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "robots.txt",
+			path:         "/robots.txt",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"Allow: /"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Client().Get(s.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(b)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			for _, s := range tt.wantContains {
+				if !strings.Contains(body, s) {
+					t.Errorf("the page doesn't contain %q", s)
+				}
+			}
+			for _, s := range tt.wantMissing {
+				if strings.Contains(body, s) {
+					t.Errorf("the page contains %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.FailNext("/luxas", http.StatusTooManyRequests, http.StatusBadGateway)
+
+	for _, want := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK, http.StatusOK} {
+		resp, err := s.Client().Get(s.URL + "/luxas")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("status = %d, want %d", resp.StatusCode, want)
+		}
+	}
+	if got := s.Requests("/luxas"); got != 4 {
+		t.Errorf("Requests() = %d, want 4", got)
+	}
+	if got := s.Requests("/janedoe"); got != 0 {
+		t.Errorf("Requests() = %d, want 0", got)
+	}
+}
+
+func TestServerETag(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL + "/janedoe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if len(etag) == 0 {
+		t.Fatal("no ETag was set")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/janedoe", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("If-None-Match", etag)
+	resp, err = s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotModified)
+	}
+}
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		pageSize, n, page  int
+		wantStart, wantEnd int
+		wantOK             bool
+	}{
+		{pageSize: 2, n: 5, page: 1, wantStart: 0, wantEnd: 2, wantOK: true},
+		{pageSize: 2, n: 5, page: 3, wantStart: 4, wantEnd: 5, wantOK: true},
+		{pageSize: 2, n: 5, page: 4},
+		{pageSize: 2, n: 5, page: 0},
+		{pageSize: 2, n: 0, page: 1, wantStart: 0, wantEnd: 0, wantOK: true},
+		{pageSize: 0, n: 5, page: 1, wantStart: 0, wantEnd: 5, wantOK: true},
+	}
+	for _, tt := range tests {
+		s := &Server{PageSize: tt.pageSize}
+		start, end, ok := s.pageBounds(tt.n, tt.page)
+		if start != tt.wantStart || end != tt.wantEnd || ok != tt.wantOK {
+			t.Errorf("pageBounds(%d, %d) with page size %d = %d, %d, %v, want %d, %d, %v",
+				tt.n, tt.page, tt.pageSize, start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+		}
+	}
+}
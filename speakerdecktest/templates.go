@@ -0,0 +1,316 @@
+package speakerdecktest
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// userPage is rendered by userTemplate and starsTemplate
+type userPage struct {
+	User    *User
+	Listing *listing
+}
+
+// categoryPage is rendered by categoryTemplate
+type categoryPage struct {
+	Name    string
+	Listing *listing
+}
+
+// connectionsPage is rendered by connectionsTemplate
+type connectionsPage struct {
+	User  *User
+	Users []*User
+	// NextLink is the link to the next page, or empty if this is the last page
+	NextLink string
+}
+
+// searchPage is rendered by searchTemplate
+type searchPage struct {
+	Query   string
+	Listing *listing
+}
+
+// errorPage is rendered by errorTemplate
+type errorPage struct {
+	StatusCode int
+	Status     string
+}
+
+// listing is one page of talk previews
+type listing struct {
+	Talks []listedTalk
+	// NextLink is the link to the next page, or empty if this is the last page
+	NextLink string
+}
+
+// listedTalk binds a talk to its author, and is rendered by the "preview" and talkTemplate templates
+type listedTalk struct {
+	User *User
+	Talk *Talk
+	// ShowAuthor specifies whether the author is rendered in the preview, as done on e.g. category pages
+	ShowAuthor bool
+}
+
+var (
+	funcs = template.FuncMap{
+		"slug":        categorySlug,
+		"short":       shortNumber,
+		"full":        fullNumber,
+		"description": renderDescription,
+		"slideImage":  SlideImageLink,
+		"thumbnail":   ThumbnailLink,
+		"widths":      func() []int { return SlideImageWidths },
+		"inc":         func(i int) int { return i + 1 },
+	}
+
+	baseTemplate = template.Must(template.New("base").Funcs(funcs).Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{ . }} - Speaker Deck</title>
+</head>
+<body>
+{{- end -}}
+
+{{- define "footer" -}}
+</body>
+</html>
+{{- end -}}
+
+{{- define "preview" }}
+      <div class="col-12 col-sm-6 col-lg-4 mb-5">
+        <a class="deck-preview-link" href="/{{ .User.Handle }}/{{ .Talk.ID }}" title="{{ .Talk.Title }}">
+          <div class="deck-preview" data-id="{{ .Talk.DataID }}"></div>
+          <div class="deck-title">{{ .Talk.Title }}</div>
+          <div class="deck-preview-meta">
+            <div class="deck-date">{{ .Talk.Date.Format "Jan 2, 2006" }}</div>
+            <span class="deck-stars" title="{{ full .Talk.Stars }} stars">{{ short .Talk.Stars }}</span>
+            <span class="deck-views" title="{{ full .Talk.Views }} views">{{ short .Talk.Views }}</span>
+          </div>
+        </a>
+        {{- if .ShowAuthor }}
+        <a class="deck-author" href="/{{ .User.Handle }}"><img class="avatar" src="{{ .User.AvatarLink }}" alt="{{ .User.Name }}"> {{ .User.Name }}</a>
+        {{- end }}
+      </div>
+{{- end -}}
+
+{{- define "profile" }}
+  <div class="bg-light py-4">
+    <div class="container">
+      <div class="row align-items-center">
+        <div class="col-auto"><img class="avatar" src="{{ .AvatarLink }}" alt="{{ .Name }}"></div>
+        <div class="col">
+          <h1 class="m-0">{{ .Name }}</h1>
+          <div class="text-muted">{{ .Handle }}</div>
+        </div>
+      </div>
+    </div>
+  </div>
+{{- end -}}
+
+{{- define "pagination" -}}
+{{ if .NextLink }}
+    <nav>
+      <ul class="pagination">
+        <li class="page-item next"><a class="page-link" rel="next" href="{{ .NextLink }}">Next ›</a></li>
+      </ul>
+    </nav>
+{{- end }}
+{{- end -}}
+`))
+
+	userTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .User.Name }}
+<div class="sd-main">
+  {{- template "profile" .User }}
+  <div class="container py-4">
+    <div class="deck-description"><p>{{ .User.Abstract }}</p></div>
+    <div class="row">
+      {{- range .Listing.Talks }}{{ template "preview" . }}{{ end }}
+    </div>
+    {{- template "pagination" .Listing }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	starsTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .User.Name }}
+<div class="sd-main">
+  {{- template "profile" .User }}
+  <div class="container py-4">
+    <h2 class="h4 mb-4">Starred decks</h2>
+    <div class="row">
+      {{- range .Listing.Talks }}{{ template "preview" . }}{{ end }}
+    </div>
+    {{- template "pagination" .Listing }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	connectionsTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .User.Name }}
+<div class="sd-main">
+  {{- template "profile" .User }}
+  <div class="container py-4">
+    <div class="row">
+      {{- range .Users }}
+      <div class="col-12 col-sm-6 col-lg-4 mb-4">
+        <a class="user-card" href="/{{ .Handle }}"><img class="avatar" src="{{ .AvatarLink }}" alt="{{ .Name }}"> <span class="user-card-name">{{ .Name }}</span></a>
+      </div>
+      {{- end }}
+    </div>
+    {{- template "pagination" . }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	talkTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .Talk.Title }}
+<div class="sd-main">
+  <div class="container py-4">
+    <h1 class="mb-4">{{ .Talk.Title }}</h1>
+    <div class="speakerdeck-embed" data-id="{{ .Talk.DataID }}" data-ratio="1.77777777777778"></div>
+    <meta itemprop="thumbnailUrl" content="{{ thumbnail .Talk.DataID }}">
+    <div class="deck-meta">
+      <div class="row">
+        <div class="col-md-auto">
+          <div class="row align-items-center">
+            <a href="/{{ .User.Handle }}"><img class="avatar" src="{{ .User.AvatarLink }}" alt="{{ .User.Name }}"> {{ .User.Name }}</a>
+          </div>
+          <div class="row">
+            <div class="col-auto"><a href="/c/{{ slug .Talk.Category }}">{{ .Talk.Category }}</a></div>
+            <div class="col-auto"><a href="/{{ .User.Handle }}/{{ .Talk.ID }}/stargazers">{{ short .Talk.Stars }}</a></div>
+            <div class="col-auto"><span title="{{ full .Talk.Views }} views">{{ short .Talk.Views }}</span></div>
+            <div class="col-auto"><a href="{{ .Talk.DownloadLink }}">Download PDF</a></div>
+          </div>
+        </div>
+        <div class="col-auto text-muted">
+          {{ .Talk.Date.Format "January 02, 2006" }}
+        </div>
+      </div>
+    </div>
+    <div class="deck-description mb-4">
+      {{- description .Talk.Description }}
+    </div>
+    {{- if .Talk.Slides }}
+    <div class="deck-transcript mb-4">
+      <h2 class="h5 mb-3">Transcript</h2>
+      <ol class="list-unstyled">
+        {{- range $i, $text := .Talk.Slides }}
+        <li id="slide{{ inc $i }}">
+          <a href="/{{ $.User.Handle }}/{{ $.Talk.ID }}?slide={{ inc $i }}">
+            <img src="{{ slideImage $.Talk.DataID $i (index widths 0) }}" srcset="{{ range $j, $w := widths }}{{ if $j }}, {{ end }}{{ slideImage $.Talk.DataID $i $w }} {{ $w }}w{{ end }}" alt="Slide {{ inc $i }}" loading="lazy">
+          </a>
+          <p>{{ $text }}</p>
+        </li>
+        {{- end }}
+      </ol>
+    </div>
+    {{- end }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	categoryTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .Name }}
+<div class="sd-main">
+  <div class="container py-4">
+    <h1 class="category-title">{{ .Name }}</h1>
+    <div class="row">
+      {{- range .Listing.Talks }}{{ template "preview" . }}{{ end }}
+    </div>
+    {{- template "pagination" .Listing }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	searchTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .Query }}
+<div class="sd-main">
+  <div class="container py-4">
+    <h1 class="search-title">Search results for “{{ .Query }}”</h1>
+    {{- if not .Listing.Talks }}
+    <p class="text-muted">No decks found.</p>
+    {{- end }}
+    <div class="row">
+      {{- range .Listing.Talks }}{{ template "preview" . }}{{ end }}
+    </div>
+    {{- template "pagination" .Listing }}
+  </div>
+</div>
+{{ template "footer" }}
+`))
+
+	errorTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+{{- template "header" .Status }}
+<div class="sd-main">
+  <div class="container py-4">
+    <h1>{{ .StatusCode }} {{ .Status }}</h1>
+  </div>
+</div>
+{{ template "footer" }}
+`))
+)
+
+var descriptionLinkRegexp = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// renderDescription renders a talk description the way Speakerdeck does: paragraphs are
+// separated by blank lines, lines within a paragraph by <br>, and links are made clickable
+func renderDescription(desc string) template.HTML {
+	b := &strings.Builder{}
+	for _, paragraph := range strings.Split(desc, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for i, line := range lines {
+			lines[i] = autolink(line)
+		}
+		fmt.Fprintf(b, "\n      <p>%s</p>", strings.Join(lines, "<br>\n"))
+	}
+	return template.HTML(b.String())
+}
+
+// autolink HTML-escapes s, and wraps all links in it in anchors
+func autolink(s string) string {
+	b := &strings.Builder{}
+	last := 0
+	for _, loc := range descriptionLinkRegexp.FindAllStringIndex(s, -1) {
+		// Trailing punctuation belongs to the surrounding text, as do unbalanced closing parentheses
+		for strings.ContainsAny(s[loc[1]-1:loc[1]], ".,;:!?)") &&
+			(s[loc[1]-1] != ')' || strings.Count(s[loc[0]:loc[1]], "(") < strings.Count(s[loc[0]:loc[1]], ")")) {
+			loc[1]--
+		}
+		link := html.EscapeString(s[loc[0]:loc[1]])
+		b.WriteString(html.EscapeString(s[last:loc[0]]))
+		fmt.Fprintf(b, `<a href="%s" target="_blank" rel="nofollow noopener">%s</a>`, link, link)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(s[last:]))
+	return b.String()
+}
+
+// shortNumber formats n the way Speakerdeck does in compact places, e.g. "1.2k" for 1200
+func shortNumber(n uint32) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	return strings.Replace(fmt.Sprintf("%.1fk", float64(n)/1000), ".0k", "k", 1)
+}
+
+// fullNumber formats n with thousands separators, e.g. "1,200" for 1200
+func fullNumber(n uint32) string {
+	s := fmt.Sprintf("%d", n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package speakerdeck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocolly/colly"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
+)
+
+// ScrapeStars returns the talks the given user has starred.
+// ScrapeStars uses DefaultClient, see Client.ScrapeStars.
+func ScrapeStars(userHandle string, opts *scraper.ScrapeOptions) (*StarredTalks, error) {
+	return DefaultClient.ScrapeStars(userHandle, opts)
+}
+
+// ScrapeStarsContext works like ScrapeStars, but stops scraping when ctx is done.
+// ScrapeStarsContext uses DefaultClient, see Client.ScrapeStarsContext.
+func ScrapeStarsContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*StarredTalks, error) {
+	return DefaultClient.ScrapeStarsContext(ctx, userHandle, opts)
+}
+
+// ScrapeStars returns the talks the given user has starred, together with their authors
+func (c *Client) ScrapeStars(userHandle string, opts *scraper.ScrapeOptions) (*StarredTalks, error) {
+	return c.ScrapeStarsContext(context.Background(), userHandle, opts)
+}
+
+// ScrapeStarsContext works like ScrapeStars, but stops scraping when ctx is done. No further
+// pages of starred talks are visited after ctx is cancelled.
+func (c *Client) ScrapeStarsContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*StarredTalks, error) {
+	if len(userHandle) == 0 {
+		return nil, fmt.Errorf("userHandle is mandatory!")
+	}
+
+	starsURL := c.url(userHandle, "stars")
+	stars, err := typed.Scrape[StarredTalks](ctx, starsURL, (&StarsScraper{}).Typed(), c.scrapeOptions(opts))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &UserError{Handle: userHandle, Err: err}
+	}
+	stars.Handle = userHandle
+	stars.Link = starsURL
+	return stars, nil
+}
+
+var _ scraper.Scraper = &StarsScraper{}
+
+// StarsScraper implements scraper.Scraper. Its typed.Scraper[StarredTalks] is returned by Typed
+type StarsScraper struct{}
+
+// Name returns the name of the StarsScraper
+func (s *StarsScraper) Name() string {
+	return "StarsScraper"
+}
+
+// Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
+// of them
+func (s *StarsScraper) Hooks() []scraper.Hook {
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *StarsScraper) InitialData() interface{} {
+	return NewStarredTalks()
+}
+
+// Typed returns the StarsScraper as a typed.Scraper[StarredTalks], whose handlers get the *StarredTalks
+// passed directly
+func (s *StarsScraper) Typed() typed.Scraper[StarredTalks] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewStarredTalks)
+}
+
+func (s *StarsScraper) typedHooks() []typed.Hook[StarredTalks] {
+	return []typed.Hook[StarredTalks]{
+		{
+			DOMPath: ".container .row > div:has(a[href][title])",
+			Handler: onStarredTalkFound,
+		},
+		{
+			DOMPath:    ".next .page-link[rel='next']",
+			Handler:    onNextPage[StarredTalks],
+			MaxMatches: 1,
+		},
+	}
+}
+
+func onStarredTalkFound(e *colly.HTMLElement, s *StarredTalks) (*string, error) {
+	t, err := parseAuthoredTalkPreview(e)
+	if err != nil {
+		return nil, err
+	}
+	s.TalkPreviews = append(s.TalkPreviews, *t)
+	return nil, nil
+}
@@ -0,0 +1,107 @@
+package speakerdeck_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+// starredTalks returns the talks starred by the user, in the order they are listed
+func starredTalks(users []speakerdecktest.User, handle string) []listedTalk {
+	talks := []listedTalk{}
+	for _, u := range users {
+		if u.Handle != handle {
+			continue
+		}
+		for _, ref := range u.Stars {
+			author, id, _ := strings.Cut(ref, "/")
+			for i := range users {
+				for _, t := range users[i].Talks {
+					if users[i].Handle == author && t.ID == id {
+						talks = append(talks, listedTalk{user: &users[i], talk: t})
+					}
+				}
+			}
+		}
+	}
+	return talks
+}
+
+func TestScrapeStars(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	tests := []struct {
+		name     string
+		handle   string
+		pageSize int
+		maxPages int
+		// wantTalks is how many of the starred talks are expected, in order
+		wantTalks int
+		wantPages int
+		wantErr   error
+	}{
+		{
+			name:      "paginated stars",
+			handle:    "janedoe",
+			wantTalks: 3,
+			wantPages: 2,
+		},
+		{
+			name:      "all stars on one page",
+			handle:    "janedoe",
+			pageSize:  10,
+			wantTalks: 3,
+			wantPages: 1,
+		},
+		{
+			name:      "MaxPages",
+			handle:    "janedoe",
+			maxPages:  1,
+			wantTalks: 2,
+			wantPages: 1,
+		},
+		{
+			name:      "no stars",
+			handle:    "kubefan",
+			wantTalks: 0,
+			wantPages: 1,
+		},
+		{
+			name:      "unknown user",
+			handle:    "nobody",
+			wantPages: 1,
+			wantErr:   speakerdeck.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			if tt.pageSize > 0 {
+				s.PageSize = tt.pageSize
+			}
+
+			stars, err := s.NewClient().ScrapeStars(tt.handle, &scraper.ScrapeOptions{MaxPages: tt.maxPages})
+			if got := s.Requests("/" + tt.handle + "/stars"); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScrapeStars() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScrapeStars() error = %v", err)
+			}
+
+			if link := s.URL + "/" + tt.handle + "/stars"; stars.Handle != tt.handle || stars.Link != link {
+				t.Errorf("stars of %q (%q), want %q (%q)", stars.Handle, stars.Link, tt.handle, link)
+			}
+			checkAuthoredTalkPreviews(t, s.URL, stars.TalkPreviews, starredTalks(users, tt.handle)[:tt.wantTalks])
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package speakerdeck
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path"
@@ -10,62 +11,169 @@ import (
 
 	"github.com/gocolly/colly"
 	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
 	log "github.com/sirupsen/logrus"
 )
 
-// ScrapeTalks returns either one sepecific talk if both userHandle and talkID are set, or a set of
-// all the users' talks in detail if only userHandle is set. In opts you can set extensions
+// ScrapeTalks returns either one specific talk if both userHandle and talkID are set, or a set of
+// all the user's talks in detail if only userHandle is set. In opts, you may specify possible
+// scraping extensions, or log levels.
+// ScrapeTalks uses DefaultClient, see Client.ScrapeTalks.
 func ScrapeTalks(userHandle, talkID string, opts *scraper.ScrapeOptions) (Talks, error) {
+	return DefaultClient.ScrapeTalks(userHandle, talkID, opts)
+}
+
+// ScrapeTalksContext works like ScrapeTalks, but stops scraping when ctx is done.
+// ScrapeTalksContext uses DefaultClient, see Client.ScrapeTalksContext.
+func ScrapeTalksContext(ctx context.Context, userHandle, talkID string, opts *scraper.ScrapeOptions) (Talks, error) {
+	return DefaultClient.ScrapeTalksContext(ctx, userHandle, talkID, opts)
+}
+
+// ScrapeTalksResult works like ScrapeTalksContext, but reports the talks that failed to be scraped.
+// ScrapeTalksResult uses DefaultClient, see Client.ScrapeTalksResult.
+func ScrapeTalksResult(ctx context.Context, userHandle, talkID string, opts *scraper.ScrapeOptions) (*TalksResult, error) {
+	return DefaultClient.ScrapeTalksResult(ctx, userHandle, talkID, opts)
+}
+
+// ScrapeTalks returns either one specific talk if both userHandle and talkID are set, or a set of
+// all the user's talks in detail if only userHandle is set. In opts, you may specify possible
+// scraping extensions, or log levels.
+func (c *Client) ScrapeTalks(userHandle, talkID string, opts *scraper.ScrapeOptions) (Talks, error) {
+	return c.ScrapeTalksContext(context.Background(), userHandle, talkID, opts)
+}
+
+// ScrapeTalksContext works like ScrapeTalks, but stops scraping when ctx is done. Talks that
+// haven't been fetched yet when ctx is cancelled are not scraped, and ctx.Err() is returned.
+// Talks that fail to be scraped are logged and left out, use ScrapeTalksResult to inspect them.
+func (c *Client) ScrapeTalksContext(ctx context.Context, userHandle, talkID string, opts *scraper.ScrapeOptions) (Talks, error) {
+	result, err := c.ScrapeTalksResult(ctx, userHandle, talkID, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, talkErr := range result.Errors {
+		log.Errorf("could not get speakerdeck talk %s/%s: %v", userHandle, talkErr.TalkID, talkErr.Err)
+	}
+	return result.Talks, nil
+}
+
+// ScrapeTalksResult works like ScrapeTalksContext, but returns both the successfully scraped talks
+// and a *TalkError for each talk that failed. If a specific talkID is given and that talk fails,
+// its *TalkError is returned directly. By default any amount of failed talks is tolerated; using
+// opts.FailFast or opts.MaxFailures the scrape can be aborted early, in which case the talks scraped
+// so far are returned together with an error wrapping ErrTooManyFailures.
+func (c *Client) ScrapeTalksResult(ctx context.Context, userHandle, talkID string, opts *scraper.ScrapeOptions) (*TalksResult, error) {
 	if len(userHandle) == 0 {
 		return nil, fmt.Errorf("userHandle is mandatory!")
 	}
 
 	// If there was a specific talk given, look it up
 	if len(talkID) > 0 {
-		talkURL := fmt.Sprintf("%s/%s/%s", speakerdeckRootURL, userHandle, talkID)
-		data, err := scraper.Scrape(talkURL, &TalkScraper{}, opts)
+		talk, err := c.scrapeTalk(ctx, userHandle, talkID, opts)
 		if err != nil {
 			return nil, err
 		}
-
-		talk := data.(*Talk)
-		return []Talk{*talk}, nil
+		return &TalksResult{Talks: []Talk{*talk}}, nil
 	}
 
-	user, err := ScrapeUser(userHandle, opts)
+	user, err := c.ScrapeUserContext(ctx, userHandle, opts)
 	if err != nil {
 		return nil, err
 	}
-	wg := &sync.WaitGroup{}
-	wg.Add(len(user.TalkPreviews))
+	// maxFailures is how many failed talks are tolerated, or -1 if any amount is
+	maxFailures := -1
+	var rateLimit *scraper.RateLimit
+	if opts != nil {
+		rateLimit = opts.RateLimit
+		if opts.MaxFailures > 0 {
+			maxFailures = opts.MaxFailures
+		}
+		if opts.FailFast {
+			maxFailures = 0
+		}
+	}
+
+	// abort cancels the remaining talks if there are too many failures
+	talksCtx, abort := context.WithCancel(ctx)
+	defer abort()
 
 	mux := &sync.Mutex{}
-	talks := make([]Talk, 0, len(user.TalkPreviews))
+	result := &TalksResult{
+		Talks: make([]Talk, 0, len(user.TalkPreviews)),
+	}
+	aborted := false
 
-	for _, t := range user.TalkPreviews {
-		go func(talkPreview TalkPreview) {
+	// Fetch the talks using a bounded amount of workers
+	previews := make(chan TalkPreview)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < rateLimit.Concurrency() && i < len(user.TalkPreviews); i++ {
+		wg.Add(1)
+		go func() {
 			defer wg.Done()
 
-			talkList, err := ScrapeTalks(user.Author.Handle, talkPreview.ID, opts)
-			if err != nil {
-				log.Errorf("could not get speakerdeck talk %s/%s", user.Author.Handle, talkPreview.ID)
-				return
+			for talkPreview := range previews {
+				talk, err := c.scrapeTalk(talksCtx, user.Author.Handle, talkPreview.ID, opts)
+
+				mux.Lock()
+				switch {
+				case err == nil:
+					result.Talks = append(result.Talks, *talk)
+				case talksCtx.Err() == nil:
+					result.Errors = append(result.Errors, err.(*TalkError))
+					if maxFailures >= 0 && len(result.Errors) > maxFailures {
+						aborted = true
+						abort()
+					}
+				}
+				mux.Unlock()
 			}
-			mux.Lock()
-			talks = append(talks, talkList...)
-			mux.Unlock()
-		}(t)
+		}()
 	}
+
+feed:
+	for _, t := range user.TalkPreviews {
+		// Don't start fetching more talks if the scrape was cancelled or aborted
+		select {
+		case previews <- t:
+		case <-talksCtx.Done():
+			break feed
+		}
+	}
+	close(previews)
 	wg.Wait()
 
-	sortedTalks := Talks(talks)
-	sort.Sort(sortedTalks)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	return sortedTalks, nil
+	sort.Sort(result.Talks)
+	if aborted {
+		return result, fmt.Errorf("%w: %d talks failed, last error: %v", ErrTooManyFailures, len(result.Errors), result.Errors[len(result.Errors)-1])
+	}
+	return result, nil
 }
 
-// TalkScraper implements scraper.Scraper
-type TalkScraper struct{}
+// scrapeTalk scrapes one specific talk. The returned error is always a *TalkError
+func (c *Client) scrapeTalk(ctx context.Context, userHandle, talkID string, opts *scraper.ScrapeOptions) (*Talk, error) {
+	talkURL := c.url(userHandle, talkID)
+	talk, err := typed.Scrape[Talk](ctx, talkURL, (&TalkScraper{MetadataKeys: c.MetadataKeys, VideoRecognizers: c.VideoRecognizers}).Typed(), c.scrapeOptions(opts))
+	if err != nil {
+		return nil, newTalkError(talkID, talkURL, err)
+	}
+	return talk, nil
+}
+
+var _ scraper.Scraper = &TalkScraper{}
+
+// TalkScraper implements scraper.Scraper. Its typed.Scraper[Talk] is returned by Typed
+type TalkScraper struct {
+	// MetadataKeys are the "Key: value" metadata keys recognised in the talk description.
+	// If nil, DefaultMetadataKeys is used
+	MetadataKeys []string
+
+	// VideoRecognizers recognise the links to recordings in the talk description.
+	// If nil, DefaultVideoRecognizers is used
+	VideoRecognizers []VideoRecognizer
+}
 
 // Name returns the name of the TalkScraper
 func (s *TalkScraper) Name() string {
@@ -75,130 +183,181 @@ func (s *TalkScraper) Name() string {
 // Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
 // of them
 func (s *TalkScraper) Hooks() []scraper.Hook {
-	return []scraper.Hook{
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *TalkScraper) InitialData() interface{} {
+	return NewTalk()
+}
+
+// Typed returns the TalkScraper as a typed.Scraper[Talk], whose handlers get the *Talk passed directly
+func (s *TalkScraper) Typed() typed.Scraper[Talk] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewTalk)
+}
+
+func (s *TalkScraper) typedHooks() []typed.Hook[Talk] {
+	return []typed.Hook[Talk]{
 		{
-			DOMPath: ".container h1.mb-4",
-			Handler: onTalkTitle,
+			DOMPath:    ".container h1.mb-4",
+			Handler:    onTalkTitle,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".col-auto.text-muted",
-			Handler: onTalkDate,
+			DOMPath:    ".col-auto.text-muted",
+			Handler:    onTalkDate,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
 			DOMPath: ".deck-description.mb-4 p",
-			Handler: onTalkDescription,
+			Handler: s.onTalkDescription,
 		},
 		{
-			DOMPath: ".speakerdeck-embed",
-			Handler: onTalkDataID,
+			DOMPath:    ".deck-description.mb-4",
+			Handler:    s.onTalkLinks,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".deck-meta .col-md-auto .row > div:nth-child(1) a",
-			Handler: onTalkCategory,
+			DOMPath:    ".speakerdeck-embed",
+			Handler:    onTalkDataID,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".deck-meta .col-md-auto .row > div:nth-child(2) a",
-			Handler: onTalkStars,
+			DOMPath:    ".deck-meta .col-md-auto .row > div:nth-child(1) a",
+			Handler:    onTalkCategory,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".deck-meta .col-md-auto .row > div:nth-child(3) span[title]",
-			Handler: onTalkViews,
+			DOMPath:    ".deck-meta .col-md-auto .row > div:nth-child(2) a",
+			Handler:    onTalkStars,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".deck-meta .col-md-auto .row > div:nth-child(4) a",
-			Handler: onTalkDownloadLink,
+			DOMPath:    ".deck-meta .col-md-auto .row > div:nth-child(3) span[title]",
+			Handler:    onTalkViews,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
-			DOMPath: ".deck-meta .col-md-auto .row > a:nth-child(1)",
-			Handler: onTalkAuthor,
+			DOMPath:    ".deck-meta .col-md-auto .row > div:nth-child(4) a",
+			Handler:    onTalkDownloadLink,
+			MaxMatches: 1,
+		},
+		{
+			DOMPath:    ".deck-meta .col-md-auto .row > a:nth-child(1)",
+			Handler:    onTalkAuthor,
+			Required:   true,
+			MaxMatches: 1,
 		},
 	}
 }
 
-// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
-// This pointer is passed as the second argument to all handlers. The handlers can cast it from interface{}
-// to its real type, and modify its data.
-func (s *TalkScraper) InitialData() interface{} {
-	return NewTalk()
-}
-
-func onTalkTitle(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
+func onTalkTitle(e *colly.HTMLElement, t *Talk) (*string, error) {
 	t.Title = e.Text
 	return nil, nil
 }
 
-func onTalkDataID(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
+func onTalkDataID(e *colly.HTMLElement, t *Talk) (*string, error) {
 	t.DataID = e.Attr("data-id")
 	return nil, nil
 }
 
-func onTalkDate(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
-
+func onTalkDate(e *colly.HTMLElement, t *Talk) (*string, error) {
 	d, err := parseDate(e.Text)
 	if err != nil {
-		return nil, err
+		return nil, &scraper.ParseError{Text: e.Text, Err: err}
 	}
 	t.Date = d
 	return nil, nil
 }
 
-func onTalkDescription(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
-	links := linkRegexp.FindStringSubmatch(e.Text)
-	for _, link := range links {
-		parsedLink, err := url.Parse(link)
+func (s *TalkScraper) onTalkDescription(e *colly.HTMLElement, t *Talk) (*string, error) {
+	keys := s.MetadataKeys
+	if keys == nil {
+		keys = DefaultMetadataKeys
+	}
+	t.Metadata.parse(elementText(e), keys)
+	t.Hide = t.Metadata.Bool("Hide")
+	return nil, nil
+}
+
+// onTalkLinks extracts the links of the whole description. It runs after onTalkDescription, so
+// the metadata of the description has been parsed already.
+func (s *TalkScraper) onTalkLinks(e *colly.HTMLElement, t *Talk) (*string, error) {
+	t.Links = extractLinks(e)
+	links := make([]string, 0, len(t.Links))
+	for _, link := range t.Links {
+		links = append(links, link.URL)
+		parsedLink, err := url.Parse(link.URL)
 		if err != nil {
-			log.Warnf("Could not parse link %q", link)
 			continue
 		}
-		t.ExtraLinks[parsedLink.Host] = append(t.ExtraLinks[parsedLink.Host], parsedLink.String())
+		t.ExtraLinks[parsedLink.Host] = append(t.ExtraLinks[parsedLink.Host], link.URL)
 	}
 
-	if strings.Contains(e.Text, "Hide: true") {
-		t.Hide = true
+	recognizers := s.VideoRecognizers
+	if recognizers == nil {
+		recognizers = DefaultVideoRecognizers
+	}
+	for _, link := range append(links, t.Metadata.Values("Video")...) {
+		parsedLink, err := url.Parse(strings.TrimSpace(link))
+		if err != nil {
+			continue
+		}
+		if v := recognizeVideo(recognizers, parsedLink); v != nil {
+			t.addVideo(*v)
+		}
 	}
-
 	return nil, nil
 }
 
-func onTalkCategory(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
-	t.CategoryLink = sdPrefix(e.Attr("href"))
+// addVideo adds v to the videos of the talk, unless the same video has been added already
+func (t *Talk) addVideo(v Video) {
+	for _, existing := range t.Videos {
+		if existing.Provider == v.Provider && existing.ID == v.ID {
+			return
+		}
+	}
+	t.Videos = append(t.Videos, v)
+}
+
+func onTalkCategory(e *colly.HTMLElement, t *Talk) (*string, error) {
+	t.CategoryLink = e.Request.AbsoluteURL(e.Attr("href"))
 	t.Category = strings.TrimSpace(e.Text)
 	return nil, nil
 }
 
-func onTalkStars(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
-
+func onTalkStars(e *colly.HTMLElement, t *Talk) (*string, error) {
 	var err error
-	t.Stars, err = parseNumber(e.Text)
-	return nil, err
+	if t.Stars, err = parseNumber(e.Text); err != nil {
+		return nil, &scraper.ParseError{Text: e.Text, Err: err}
+	}
+	return nil, nil
 }
 
-func onTalkViews(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
-
+func onTalkViews(e *colly.HTMLElement, t *Talk) (*string, error) {
 	viewsStr := strings.TrimSuffix(e.Attr("title"), " views")
 	var err error
-	t.Views, err = parseNumber(viewsStr)
-	return nil, err
+	if t.Views, err = parseNumber(viewsStr); err != nil {
+		return nil, &scraper.ParseError{Text: viewsStr, Err: err}
+	}
+	return nil, nil
 }
 
-func onTalkDownloadLink(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
+func onTalkDownloadLink(e *colly.HTMLElement, t *Talk) (*string, error) {
 	t.DownloadLink = e.Attr("href")
 	return nil, nil
 }
 
-func onTalkAuthor(e *colly.HTMLElement, data interface{}) (*string, error) {
-	t := data.(*Talk)
+func onTalkAuthor(e *colly.HTMLElement, t *Talk) (*string, error) {
 	t.Link = e.Request.URL.String()
 	t.ID = path.Base(t.Link)
-	t.Author.Link = sdPrefix(e.Attr("href"))
+	t.Author.Link = e.Request.AbsoluteURL(e.Attr("href"))
 	t.Author.Handle = path.Base(t.Author.Link)
 	t.Author.Name = strings.TrimSpace(e.Text)
 	t.Author.AvatarLink = httpsPrefix + e.ChildAttr("img", "src")
@@ -0,0 +1,347 @@
+package speakerdeck_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+func TestScrapeTalks(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	tests := []struct {
+		name     string
+		handle   string
+		talkID   string
+		pageSize int
+		want     []speakerdecktest.Talk
+		wantErr  error
+	}{
+		{
+			name:   "all talks of a paginated user",
+			handle: "luxas",
+			want:   users[0].Talks,
+		},
+		{
+			name:     "all talks on one page",
+			handle:   "luxas",
+			pageSize: 10,
+			want:     users[0].Talks,
+		},
+		{
+			name:   "one talk",
+			handle: "luxas",
+			talkID: "weave-ignite",
+			want:   users[0].Talks[4:],
+		},
+		{
+			name:   "no talks",
+			handle: "kubefan",
+			want:   []speakerdecktest.Talk{},
+		},
+		{
+			name:    "unknown talk",
+			handle:  "luxas",
+			talkID:  "nothing",
+			wantErr: speakerdeck.ErrTalkNotFound,
+		},
+		{
+			name:    "unknown user",
+			handle:  "nobody",
+			wantErr: speakerdeck.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			if tt.pageSize > 0 {
+				s.PageSize = tt.pageSize
+			}
+
+			talks, err := s.NewClient().ScrapeTalks(tt.handle, tt.talkID, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScrapeTalks() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScrapeTalks() error = %v", err)
+			}
+
+			if !sort.IsSorted(talks) {
+				t.Errorf("talks aren't sorted by date")
+			}
+			want := map[string]speakerdecktest.Talk{}
+			for _, talk := range tt.want {
+				want[talk.ID] = talk
+			}
+			if len(talks) != len(want) {
+				t.Fatalf("got %d talks, want %d", len(talks), len(want))
+			}
+			for _, talk := range talks {
+				w, ok := want[talk.ID]
+				if !ok {
+					t.Errorf("unexpected talk %q", talk.ID)
+					continue
+				}
+				if talk.Title != w.Title || talk.DataID != w.DataID || !talk.Date.Equal(w.Date) {
+					t.Errorf("talk %q = %q (%s, %s), want %q (%s, %s)", talk.ID, talk.Title, talk.DataID, talk.Date, w.Title, w.DataID, w.Date)
+				}
+				if talk.Category != w.Category || talk.Stars != w.Stars || talk.Views != w.Views || talk.DownloadLink != w.DownloadLink {
+					t.Errorf("talk %q has unexpected details: %+v", talk.ID, talk)
+				}
+				if talk.Author.Handle != tt.handle {
+					t.Errorf("talk %q has author %q, want %q", talk.ID, talk.Author.Handle, tt.handle)
+				}
+				if talk.Slides != nil {
+					t.Errorf("talk %q has slides without the SlidesExtension", talk.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeTalksResultFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		// failing talks respond with 404 once
+		failing     []string
+		opts        scraper.ScrapeOptions
+		wantTalks   int
+		wantErrors  int
+		wantAborted bool
+	}{
+		{
+			name:      "no failures",
+			wantTalks: 5,
+		},
+		{
+			name:       "failures are tolerated by default",
+			failing:    []string{"weave-ignite", "raspberry-pi-kubernetes"},
+			wantTalks:  3,
+			wantErrors: 2,
+		},
+		{
+			name:       "as many failures as MaxFailures",
+			failing:    []string{"weave-ignite"},
+			opts:       scraper.ScrapeOptions{MaxFailures: 1},
+			wantTalks:  4,
+			wantErrors: 1,
+		},
+		{
+			name:        "more failures than MaxFailures",
+			failing:     []string{"weave-ignite", "raspberry-pi-kubernetes"},
+			opts:        scraper.ScrapeOptions{MaxFailures: 1, RateLimit: &scraper.RateLimit{MaxConcurrency: 1}},
+			wantErrors:  2,
+			wantAborted: true,
+		},
+		{
+			name:        "FailFast",
+			failing:     []string{"weave-ignite"},
+			opts:        scraper.ScrapeOptions{FailFast: true, RateLimit: &scraper.RateLimit{MaxConcurrency: 1}},
+			wantErrors:  1,
+			wantAborted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			for _, talkID := range tt.failing {
+				s.FailNext("/luxas/"+talkID, http.StatusNotFound)
+			}
+
+			result, err := s.NewClient().ScrapeTalksResult(context.Background(), "luxas", "", &tt.opts)
+			if aborted := errors.Is(err, speakerdeck.ErrTooManyFailures); aborted != tt.wantAborted {
+				t.Fatalf("ScrapeTalksResult() error = %v, want aborted %v", err, tt.wantAborted)
+			}
+			if !tt.wantAborted && err != nil {
+				t.Fatalf("ScrapeTalksResult() error = %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("got %d errors, want %d", len(result.Errors), tt.wantErrors)
+			}
+			// Aborted scrapes may have scraped any amount of the talks
+			if !tt.wantAborted && len(result.Talks) != tt.wantTalks {
+				t.Errorf("got %d talks, want %d", len(result.Talks), tt.wantTalks)
+			}
+			for _, talkErr := range result.Errors {
+				if talkErr.StatusCode != http.StatusNotFound || !errors.Is(talkErr, speakerdeck.ErrTalkNotFound) {
+					t.Errorf("unexpected error for talk %q: %v", talkErr.TalkID, talkErr)
+				}
+				if want := fmt.Sprintf("%s/luxas/%s", s.URL, talkErr.TalkID); talkErr.URL != want {
+					t.Errorf("error URL = %q, want %q", talkErr.URL, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTalksResultJSON(t *testing.T) {
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+	s.FailNext("/luxas/weave-ignite", http.StatusNotFound)
+
+	result, err := s.NewClient().ScrapeTalksResult(context.Background(), "luxas", "", nil)
+	if err != nil {
+		t.Fatalf("ScrapeTalksResult() error = %v", err)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got struct {
+		Talks  []map[string]interface{} `json:"talks"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(got.Talks) != 4 || len(got.Errors) != 1 {
+		t.Fatalf("encoded %d talks and %d errors, want 4 and 1:\n%s", len(got.Talks), len(got.Errors), b)
+	}
+	wantErr := map[string]interface{}{
+		"talkID":     "weave-ignite",
+		"url":        s.URL + "/luxas/weave-ignite",
+		"statusCode": float64(http.StatusNotFound),
+		"error":      result.Errors[0].Err.Error(),
+	}
+	if !reflect.DeepEqual(got.Errors[0], wantErr) {
+		t.Errorf("encoded error = %v, want %v", got.Errors[0], wantErr)
+	}
+	if msg, _ := got.Errors[0]["error"].(string); len(msg) == 0 {
+		t.Error("encoded error has no message")
+	}
+
+	// Optional fields are left out, and errors without Err have an empty message
+	b, err = json.Marshal(&speakerdeck.TalkError{TalkID: "talk", URL: "https://speakerdeck.com/luxas/talk"})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"talkID":"talk","url":"https://speakerdeck.com/luxas/talk","error":""}`; string(b) != want {
+		t.Errorf("encoded error = %s, want %s", b, want)
+	}
+}
+
+func TestScrapeTalkSlides(t *testing.T) {
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+	want := speakerdecktest.DefaultUsers()[0].Talks[0]
+
+	opts := &scraper.ScrapeOptions{Extensions: []scraper.Extension{&speakerdeck.SlidesExtension{}}}
+	talks, err := s.NewClient().ScrapeTalks("luxas", want.ID, opts)
+	if err != nil {
+		t.Fatalf("ScrapeTalks() error = %v", err)
+	}
+	slides := talks[0].Slides
+	if slides == nil {
+		t.Fatal("no slides were scraped")
+	}
+	if slides.Count != len(want.Slides) || len(slides.Pages) != len(want.Slides) {
+		t.Fatalf("got %d slides (%d pages), want %d", slides.Count, len(slides.Pages), len(want.Slides))
+	}
+	if slides.ThumbnailLink != speakerdecktest.ThumbnailLink(want.DataID) {
+		t.Errorf("thumbnail = %q, want %q", slides.ThumbnailLink, speakerdecktest.ThumbnailLink(want.DataID))
+	}
+	for i, slide := range slides.Pages {
+		if slide.Number != i+1 || slide.Text != want.Slides[i] {
+			t.Errorf("slide %d = %d %q, want %q", i, slide.Number, slide.Text, want.Slides[i])
+		}
+		if len(slide.Images) != len(speakerdecktest.SlideImageWidths) {
+			t.Fatalf("slide %d has %d images, want %d", i, len(slide.Images), len(speakerdecktest.SlideImageWidths))
+		}
+		for j, width := range speakerdecktest.SlideImageWidths {
+			if link := speakerdecktest.SlideImageLink(want.DataID, i, width); slide.Images[j].Width != width || slide.Images[j].Link != link {
+				t.Errorf("slide %d image %d = %+v, want %d %q", i, j, slide.Images[j], width, link)
+			}
+		}
+	}
+}
+
+func TestScrapeTalkDescription(t *testing.T) {
+	s := speakerdecktest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	tests := []struct {
+		talkID       string
+		wantMetadata speakerdeck.Metadata
+		wantLinks    []string
+		wantVideos   []speakerdeck.Video
+		wantHide     bool
+	}{
+		{
+			talkID: "getting-started-in-the-kubernetes-community",
+			wantMetadata: speakerdeck.Metadata{
+				"Event":       {"KubeCon + CloudNativeCon Europe 2019"},
+				"Co-Speakers": {"Nikhita Raghunath"},
+				"Location":    {"Fira Gran Via, Av. Joan Carles I, Barcelona, Spain"},
+			},
+			wantLinks:  []string{"https://github.com/nikhita"},
+			wantVideos: []speakerdeck.Video{},
+		},
+		{
+			talkID:       "cloud-native-nordics-meetup",
+			wantMetadata: speakerdeck.Metadata{"Location": {"Online"}},
+			wantLinks:    []string{"https://vimeo.com/channels/cloudnative/123456789#t=1m30s"},
+			wantVideos: []speakerdeck.Video{
+				{Provider: "vimeo", ID: "123456789", Start: 90, Link: "https://vimeo.com/123456789#t=90s"},
+			},
+		},
+		{
+			talkID:       "raspberry-pi-kubernetes",
+			wantMetadata: speakerdeck.Metadata{"Hide": {"true"}},
+			wantLinks:    []string{},
+			wantVideos:   []speakerdeck.Video{},
+			wantHide:     true,
+		},
+		{
+			talkID:       "weave-ignite",
+			wantMetadata: speakerdeck.Metadata{"Video": {"https://youtu.be/aqcQhQ3R7dI?t=90"}},
+			wantLinks: []string{
+				"https://github.com/weaveworks/ignite",
+				"https://ignite.readthedocs.io/en/stable/",
+				"https://youtu.be/aqcQhQ3R7dI?t=90",
+			},
+			wantVideos: []speakerdeck.Video{
+				{Provider: "youtube", ID: "aqcQhQ3R7dI", Start: 90, Link: "https://www.youtube.com/watch?v=aqcQhQ3R7dI&t=90s"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.talkID, func(t *testing.T) {
+			talks, err := c.ScrapeTalks("luxas", tt.talkID, nil)
+			if err != nil {
+				t.Fatalf("ScrapeTalks() error = %v", err)
+			}
+			talk := talks[0]
+			if !reflect.DeepEqual(talk.Metadata, tt.wantMetadata) {
+				t.Errorf("metadata = %v, want %v", talk.Metadata, tt.wantMetadata)
+			}
+			links := []string{}
+			for _, link := range talk.Links {
+				links = append(links, link.URL)
+			}
+			if !reflect.DeepEqual(links, tt.wantLinks) {
+				t.Errorf("links = %q, want %q", links, tt.wantLinks)
+			}
+			if !reflect.DeepEqual(talk.Videos, tt.wantVideos) {
+				t.Errorf("videos = %+v, want %+v", talk.Videos, tt.wantVideos)
+			}
+			if talk.Hide != tt.wantHide {
+				t.Errorf("hide = %v, want %v", talk.Hide, tt.wantHide)
+			}
+		})
+	}
+}
@@ -60,10 +60,100 @@ type TalkPreview struct {
 	DataID string `json:"dataID"`
 }
 
+// NewCategory creates a new Category object
+func NewCategory() *Category {
+	return &Category{
+		TalkPreviews: []AuthoredTalkPreview{},
+	}
+}
+
+// Category represents a category as browsed on the category page (i.e. https://speakerdeck.com/c/{category})
+type Category struct {
+	// Name describes the human-friendly name of the category, e.g. "Technology"
+	Name string `json:"name"`
+
+	// Link describes the link to the first page of the category
+	Link string `json:"link"`
+
+	// TalkPreviews is a list of the talks in the category, as seen on the category pages
+	TalkPreviews []AuthoredTalkPreview `json:"talkPreviews"`
+}
+
+// NewSearchResults creates a new SearchResults object
+func NewSearchResults() *SearchResults {
+	return &SearchResults{
+		TalkPreviews: []AuthoredTalkPreview{},
+	}
+}
+
+// SearchResults represents the talks found when searching Speakerdeck (i.e. https://speakerdeck.com/search?q={query})
+type SearchResults struct {
+	// Query is the search query, e.g. "kubernetes"
+	Query string `json:"query"`
+
+	// Link describes the link to the first page of the search results
+	Link string `json:"link"`
+
+	// TalkPreviews is a list of the talks found, in the order Speakerdeck ranks them
+	TalkPreviews []AuthoredTalkPreview `json:"talkPreviews"`
+}
+
+// NewStarredTalks creates a new StarredTalks object
+func NewStarredTalks() *StarredTalks {
+	return &StarredTalks{
+		TalkPreviews: []AuthoredTalkPreview{},
+	}
+}
+
+// StarredTalks represents the talks a user has starred (i.e. https://speakerdeck.com/{user-handle}/stars)
+type StarredTalks struct {
+	// Handle is the handle of the user that has starred the talks
+	Handle string `json:"handle"`
+
+	// Link describes the link to the first page of starred talks
+	Link string `json:"link"`
+
+	// TalkPreviews is a list of the starred talks, together with their authors
+	TalkPreviews []AuthoredTalkPreview `json:"talkPreviews"`
+}
+
+// NewConnections creates a new Connections object
+func NewConnections() *Connections {
+	return &Connections{
+		Authors: []Author{},
+	}
+}
+
+// Connections represents the users following a user (i.e. https://speakerdeck.com/{user-handle}/followers),
+// or the users a user is following (i.e. https://speakerdeck.com/{user-handle}/following)
+type Connections struct {
+	// Author describes the user whose followers or followed users are listed
+	Author Author `json:"author"`
+
+	// Link describes the link to the first page of the list
+	Link string `json:"link"`
+
+	// Authors is the list of followers or followed users
+	Authors []Author `json:"authors"`
+}
+
+// AuthoredTalkPreview contains the information about a talk that can be seen on pages listing talks of
+// many users, e.g. category, search and stars pages
+type AuthoredTalkPreview struct {
+	// TalkPreview is embedded here as it contains all the data we want to display here, too
+	TalkPreview
+
+	// Author describes the Speakerdeck profile of the person that's created the presentation
+	Author Author `json:"author"`
+}
+
 // NewTalk returns a new, empty talk object
 func NewTalk() *Talk {
 	return &Talk{
+		Links:      []Link{},
 		ExtraLinks: map[string][]string{},
+		Metadata:   Metadata{},
+		Videos:     []Video{},
 	}
 }
 
@@ -89,10 +179,26 @@ type Talk struct {
 	// DownloadLink is the link from where you can download the underlying PDF
 	DownloadLink string `json:"downloadLink"`
 
-	// ExtraLinks contains parsed URLs found in the talk description, mapped by their domain name
+	// Slides describes the slides of the presentation, as listed in the talk transcript.
+	// This field is only populated by the SlidesExtension
+	Slides *Slides `json:"slides,omitempty"`
+
+	// Links contains the links found in the talk description, both in anchors and plain text. The links
+	// are canonicalised, e.g. stripped from tracking parameters, and de-duplicated
+	Links []Link `json:"links"`
+
+	// ExtraLinks contains the URLs of Links, mapped by their domain name
 	ExtraLinks map[string][]string `json:"extraLinks"`
 
-	// Hide is set to true if the talk description contains a "Hide: true" string,
+	// Metadata contains the "Key: value" lines of the talk description with recognised keys,
+	// e.g. "Event: KubeCon EU 2019". See DefaultMetadataKeys for the keys recognised by default
+	Metadata Metadata `json:"metadata"`
+
+	// Videos contains the recordings of the talk linked to in the talk description, or given using
+	// "Video: <link>" metadata. See DefaultVideoRecognizers for the video hosts recognised by default
+	Videos []Video `json:"videos"`
+
+	// Hide is set to true if the talk description contains "Hide: true" metadata,
 	// indicating it should not be visible for the API. If a talk has set Hide=true,
 	// it will be returned as normal, and the user of the API can choose whether to ignore
 	// or respect that pledge
@@ -102,10 +208,73 @@ type Talk struct {
 	// This field is populated by the LocationExtension, and is set based on
 	// a "Location: <address>" string in the talk description. For instance,
 	// if you put "Location: TUAS-talo, Aalto University" in the talk description,
-	// Location will be populated with coordinates based on Google Maps data.
+	// Location will be populated with coordinates and address details from the geocoder.
 	Location *Location `json:"location,omitempty"`
 }
 
+// Link describes a link found in a talk description
+type Link struct {
+	// URL is the canonical form of the link
+	URL string `json:"url"`
+
+	// Text is the text of the anchor of the link, if it differs from the link itself
+	Text string `json:"text,omitempty"`
+}
+
+// Video describes a recording of a talk
+type Video struct {
+	// Provider is the name of the video host, e.g. "youtube" or "vimeo"
+	Provider string `json:"provider"`
+
+	// ID identifies the video at the provider, e.g. "GMdT5l-3lAQ" for YouTube
+	ID string `json:"id"`
+
+	// Start is the timestamp in seconds the video link starts playing from, if set
+	Start int `json:"start,omitempty"`
+
+	// Link is the normalised link to the video, including the start timestamp
+	Link string `json:"link"`
+}
+
+// Slides describes the slides of a presentation
+type Slides struct {
+	// Count describes how many slides the presentation has
+	Count int `json:"count"`
+
+	// ThumbnailLink is the link to a small image of the first slide
+	ThumbnailLink string `json:"thumbnailLink"`
+
+	// Pages contains the slides, in the order they are presented
+	Pages []Slide `json:"pages"`
+}
+
+// Slide describes one slide of a presentation
+type Slide struct {
+	// Number is the 1-based position of the slide in the presentation
+	Number int `json:"number"`
+
+	// Link is the link to the talk page, opened at this slide
+	Link string `json:"link"`
+
+	// ImageLink is the link to the default image of the slide
+	ImageLink string `json:"imageLink"`
+
+	// Images contains the available images of the slide, ordered by increasing width
+	Images []SlideImage `json:"images"`
+
+	// Text is the transcript of the slide, as extracted by Speakerdeck for accessibility
+	Text string `json:"text"`
+}
+
+// SlideImage describes an image of a slide at a given resolution
+type SlideImage struct {
+	// Width is the width of the image in pixels
+	Width int `json:"width"`
+
+	// Link is the link to the image
+	Link string `json:"link"`
+}
+
 // Talks orders the Talk objects by time
 type Talks []Talk
 
@@ -124,17 +293,26 @@ func (p Talks) Swap(i, j int) {
 	p[i], p[j] = p[j], p[i]
 }
 
+// TalksResult contains the talks that were scraped successfully, and the errors of the ones that failed
+type TalksResult struct {
+	// Talks contains the successfully scraped talks, ordered by time
+	Talks Talks `json:"talks"`
+
+	// Errors contains the errors of the talks that failed to be scraped
+	Errors []*TalkError `json:"errors"`
+}
+
 // Location describes a geographical location for the talk
 // This struct is populated by the LocationExtension, and is set based on
 // a "Location: <address>" string in the talk description. For instance,
 // if you put "Location: TUAS-talo, Aalto University" in the talk description,
-// Location will be populated with coordinates based on Google Maps data.
+// Location will be populated with coordinates and address details from the geocoder.
 type Location struct {
 	// RequestedAddress is populated from the original "requested" address mentioned in the
 	// talk description (e.g. "TUAS-talo, Aalto University")
 	RequestedAddress string `json:"requestedAddress"`
 
-	// ResolvedAddress is populated by the Google Maps Geocoding API, and is the official street address
+	// ResolvedAddress is populated by the geocoder, and is the official street address
 	// (or similar) of the place. In the above example: "Maarintie 8, 02150 Espoo, Finland"
 	ResolvedAddress string `json:"resolvedAddress"`
 
@@ -143,4 +321,57 @@ type Location struct {
 
 	// Lng describes the longitude of the location
 	Lng float64 `json:"lng"`
+
+	// City is the city (or similar locality) of the location, e.g. "Espoo"
+	City string `json:"city,omitempty"`
+
+	// Region is the first-level administrative division of the location, e.g. a state or province
+	Region string `json:"region,omitempty"`
+
+	// Country is the name of the country of the location, e.g. "Finland"
+	Country string `json:"country,omitempty"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country of the location, e.g. "FI"
+	CountryCode string `json:"countryCode,omitempty"`
+
+	// PlaceID identifies the place in the dataset of the geocoder, e.g. a Google Maps place ID
+	PlaceID string `json:"placeID,omitempty"`
+
+	// Types describes what kind of place the location is, e.g. ["university", "establishment"].
+	// The types depend on the geocoder
+	Types []string `json:"types,omitempty"`
+
+	// Timezone is the IANA time zone of the location, e.g. "Europe/Helsinki"
+	Timezone string `json:"timezone,omitempty"`
+
+	// Hint is populated from the "Location-Hint: <country or region>" string in the talk description,
+	// which biases the geocoder towards places in that country or region (e.g. "FI" or "Texas")
+	Hint string `json:"hint,omitempty"`
+
+	// Confidence describes how well the location matches the requested address, from 0 to 1.
+	// Explicitly given coordinates have a confidence of 1
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// Candidates contains all places the geocoder found for the requested address, the chosen one
+	// first. Many candidates with a similar confidence mean that the address is ambiguous, and
+	// should be made more specific, or be given as coordinates
+	Candidates []LocationCandidate `json:"candidates,omitempty"`
+}
+
+// LocationCandidate describes a place the geocoder found for the requested address of a Location
+type LocationCandidate struct {
+	// ResolvedAddress is the official street address (or similar) of the place
+	ResolvedAddress string `json:"resolvedAddress"`
+
+	// Lat describes the latitude of the place
+	Lat float64 `json:"lat"`
+
+	// Lng describes the longitude of the place
+	Lng float64 `json:"lng"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country of the place, if known
+	CountryCode string `json:"countryCode,omitempty"`
+
+	// Confidence describes how well the place matches the requested address, from 0 to 1
+	Confidence float64 `json:"confidence"`
 }
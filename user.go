@@ -1,33 +1,54 @@
 package speakerdeck
 
 import (
+	"context"
 	"fmt"
 	"path"
 
 	"github.com/gocolly/colly"
 	"github.com/luxas/speakerdeck-api/scraper"
+	"github.com/luxas/speakerdeck-api/scraper/typed"
 )
 
-// ScrapeUser returns an user object based on the given user handle. In opts,
+// ScrapeUser returns a user object based on the given user handle. In opts,
 // you may specify possible scraping extensions, or log levels.
+// ScrapeUser uses DefaultClient, see Client.ScrapeUser.
 func ScrapeUser(userHandle string, opts *scraper.ScrapeOptions) (*User, error) {
+	return DefaultClient.ScrapeUser(userHandle, opts)
+}
+
+// ScrapeUserContext works like ScrapeUser, but stops scraping when ctx is done.
+// ScrapeUserContext uses DefaultClient, see Client.ScrapeUserContext.
+func ScrapeUserContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*User, error) {
+	return DefaultClient.ScrapeUserContext(ctx, userHandle, opts)
+}
+
+// ScrapeUser returns a user object based on the given user handle. In opts,
+// you may specify possible scraping extensions, or log levels.
+func (c *Client) ScrapeUser(userHandle string, opts *scraper.ScrapeOptions) (*User, error) {
+	return c.ScrapeUserContext(context.Background(), userHandle, opts)
+}
+
+// ScrapeUserContext works like ScrapeUser, but stops scraping when ctx is done. No further
+// pages of the user's talk list are visited after ctx is cancelled.
+func (c *Client) ScrapeUserContext(ctx context.Context, userHandle string, opts *scraper.ScrapeOptions) (*User, error) {
 	if len(userHandle) == 0 {
 		return nil, fmt.Errorf("userHandle is mandatory!")
 	}
 
-	fullURL := fmt.Sprintf("%s/%s", speakerdeckRootURL, userHandle)
-
-	data, err := scraper.Scrape(fullURL, &UserScraper{}, opts)
+	user, err := typed.Scrape[User](ctx, c.url(userHandle), (&UserScraper{}).Typed(), c.scrapeOptions(opts))
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &UserError{Handle: userHandle, Err: err}
 	}
-	user := data.(*User)
 	return user, nil
 }
 
 var _ scraper.Scraper = &UserScraper{}
 
-// UserScraper implements scraper.Scraper
+// UserScraper implements scraper.Scraper. Its typed.Scraper[User] is returned by Typed
 type UserScraper struct{}
 
 // Name returns the name of the UserScraper
@@ -38,10 +59,27 @@ func (s *UserScraper) Name() string {
 // Hooks returns mappings between DOM paths in the scraped web pages, and handler functions to extract data out
 // of them
 func (s *UserScraper) Hooks() []scraper.Hook {
-	return []scraper.Hook{
+	return typed.Adapt(s.Typed()).Hooks()
+}
+
+// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
+// This pointer is passed as the second argument to all handlers, which can modify its data.
+func (s *UserScraper) InitialData() interface{} {
+	return NewUser()
+}
+
+// Typed returns the UserScraper as a typed.Scraper[User], whose handlers get the *User passed directly
+func (s *UserScraper) Typed() typed.Scraper[User] {
+	return typed.NewScraper(s.Name(), s.typedHooks(), NewUser)
+}
+
+func (s *UserScraper) typedHooks() []typed.Hook[User] {
+	return []typed.Hook[User]{
 		{
-			DOMPath: ".sd-main > :first-child .row",
-			Handler: onUserAuthor,
+			DOMPath:    ".sd-main > :first-child .row",
+			Handler:    onUserAuthor,
+			Required:   true,
+			MaxMatches: 1,
 		},
 		{
 			DOMPath: ".deck-description p",
@@ -52,21 +90,14 @@ func (s *UserScraper) Hooks() []scraper.Hook {
 			Handler: onUserTalkFound,
 		},
 		{
-			DOMPath: ".next .page-link[rel='next']",
-			Handler: onUserNextPage,
+			DOMPath:    ".next .page-link[rel='next']",
+			Handler:    onNextPage[User],
+			MaxMatches: 1,
 		},
 	}
 }
 
-// InitialData returns the struct pointer passed around between the handler functions registered in Hooks()
-// This pointer is passed as the second argument to all handlers. The handlers can cast it from interface{}
-// to its real type, and modify its data.
-func (s *UserScraper) InitialData() interface{} {
-	return NewUser()
-}
-
-func onUserAuthor(e *colly.HTMLElement, data interface{}) (*string, error) {
-	u := data.(*User)
+func onUserAuthor(e *colly.HTMLElement, u *User) (*string, error) {
 	u.Author.Link = e.Request.URL.String()
 	u.Author.Name = e.ChildText("h1.m-0")
 	u.Author.Handle = e.ChildText("div.text-muted")
@@ -74,41 +105,50 @@ func onUserAuthor(e *colly.HTMLElement, data interface{}) (*string, error) {
 	return nil, nil
 }
 
-func onUserAbstract(e *colly.HTMLElement, data interface{}) (*string, error) {
-	u := data.(*User)
+func onUserAbstract(e *colly.HTMLElement, u *User) (*string, error) {
 	u.Abstract = e.Text
 	return nil, nil
 }
 
-func onUserTalkFound(e *colly.HTMLElement, data interface{}) (*string, error) {
-	u := data.(*User)
-
-	stars, err := parseNumber(e.ChildText(".deck-preview-meta > :nth-child(2)"))
+func onUserTalkFound(e *colly.HTMLElement, u *User) (*string, error) {
+	t, err := parseTalkPreview(e)
 	if err != nil {
 		return nil, err
 	}
-	views, err := parseNumber(e.ChildText(".deck-preview-meta > :nth-child(3)"))
+	u.TalkPreviews = append(u.TalkPreviews, *t)
+	return nil, nil
+}
+
+// parseTalkPreview parses the preview card of a talk, as listed on e.g. user and category pages.
+// e is the anchor element linking to the talk
+func parseTalkPreview(e *colly.HTMLElement) (*TalkPreview, error) {
+	starsStr := e.ChildText(".deck-preview-meta > :nth-child(2)")
+	stars, err := parseNumber(starsStr)
 	if err != nil {
-		return nil, err
+		return nil, &scraper.ParseError{Text: starsStr, Err: err}
+	}
+	viewsStr := e.ChildText(".deck-preview-meta > :nth-child(3)")
+	views, err := parseNumber(viewsStr)
+	if err != nil {
+		return nil, &scraper.ParseError{Text: viewsStr, Err: err}
 	}
 
-	t := TalkPreview{
+	t := &TalkPreview{
 		Title:  e.Attr("title"),
-		Link:   sdPrefix(e.Attr("href")),
+		Link:   e.Request.AbsoluteURL(e.Attr("href")),
 		DataID: e.ChildAttr("div.deck-preview", "data-id"),
 		Views:  views,
 		Stars:  stars,
 	}
 	t.ID = path.Base(t.Link)
-
-	u.TalkPreviews = append(u.TalkPreviews, t)
-	return nil, nil
+	return t, nil
 }
 
-func onUserNextPage(e *colly.HTMLElement, _ interface{}) (*string, error) {
+// onNextPage returns the link to the next page of paginated listings, e.g. user and category pages
+func onNextPage[T any](e *colly.HTMLElement, _ *T) (*string, error) {
 	href := e.Attr("href")
 	if len(href) > 0 {
-		nextURL := sdPrefix(e.Attr("href"))
+		nextURL := e.Request.AbsoluteURL(href)
 		return &nextURL, nil
 	}
 	return nil, nil
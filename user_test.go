@@ -0,0 +1,96 @@
+package speakerdeck_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luxas/speakerdeck-api"
+	"github.com/luxas/speakerdeck-api/speakerdecktest"
+)
+
+func TestScrapeUser(t *testing.T) {
+	users := speakerdecktest.DefaultUsers()
+	tests := []struct {
+		name     string
+		handle   string
+		pageSize int
+		want     *speakerdecktest.User
+		// wantPages is how many pages of the user are expected to be fetched
+		wantPages int
+		wantErr   error
+	}{
+		{
+			name:      "paginated user",
+			handle:    "luxas",
+			want:      &users[0],
+			wantPages: 3,
+		},
+		{
+			name:      "all talks on one page",
+			handle:    "luxas",
+			pageSize:  10,
+			want:      &users[0],
+			wantPages: 1,
+		},
+		{
+			name:      "one talk",
+			handle:    "janedoe",
+			want:      &users[1],
+			wantPages: 1,
+		},
+		{
+			name:      "no talks",
+			handle:    "kubefan",
+			want:      &users[2],
+			wantPages: 1,
+		},
+		{
+			name:      "unknown user",
+			handle:    "nobody",
+			wantPages: 1,
+			wantErr:   speakerdeck.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := speakerdecktest.NewServer()
+			defer s.Close()
+			if tt.pageSize > 0 {
+				s.PageSize = tt.pageSize
+			}
+
+			user, err := s.NewClient().ScrapeUser(tt.handle, nil)
+			if got := s.Requests("/" + tt.handle); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScrapeUser() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScrapeUser() error = %v", err)
+			}
+
+			if user.Author.Name != tt.want.Name || user.Author.Handle != tt.want.Handle {
+				t.Errorf("author = %q (%q), want %q (%q)", user.Author.Name, user.Author.Handle, tt.want.Name, tt.want.Handle)
+			}
+			if user.Abstract != tt.want.Abstract {
+				t.Errorf("abstract = %q, want %q", user.Abstract, tt.want.Abstract)
+			}
+			if len(user.TalkPreviews) != len(tt.want.Talks) {
+				t.Fatalf("got %d talks, want %d", len(user.TalkPreviews), len(tt.want.Talks))
+			}
+			for i, preview := range user.TalkPreviews {
+				want := tt.want.Talks[i]
+				if preview.ID != want.ID || preview.Title != want.Title || preview.DataID != want.DataID {
+					t.Errorf("talk %d = %q (%q), want %q (%q)", i, preview.ID, preview.Title, want.ID, want.Title)
+				}
+				if preview.Stars != want.Stars || preview.Views != want.Views {
+					t.Errorf("talk %q has %d stars and %d views, want %d and %d", preview.ID, preview.Stars, preview.Views, want.Stars, want.Views)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package speakerdeck
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultVideoRecognizers are the VideoRecognizers used to find recordings in talk descriptions, unless
+// Client.VideoRecognizers is set
+var DefaultVideoRecognizers = []VideoRecognizer{
+	&YouTubeRecognizer{},
+	&VimeoRecognizer{},
+}
+
+// VideoRecognizer recognises links to video recordings. Implement it to recognise the videos of hosts
+// not supported out of the box, and set it in Client.VideoRecognizers together with the defaults.
+type VideoRecognizer interface {
+	// RecognizeVideo returns the video the link points to, or nil if the link isn't a video
+	// recognised by this VideoRecognizer
+	RecognizeVideo(link *url.URL) *Video
+}
+
+// recognizeVideo returns the video recognised by the first of the recognizers that recognises link
+func recognizeVideo(recognizers []VideoRecognizer, link *url.URL) *Video {
+	for _, r := range recognizers {
+		if v := r.RecognizeVideo(link); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+var _ VideoRecognizer = &YouTubeRecognizer{}
+
+// YouTubeRecognizer recognises YouTube videos, linked to in any of the youtu.be/{id}, youtube.com/watch?v={id},
+// youtube.com/embed/{id}, youtube.com/shorts/{id} and youtube.com/live/{id} forms
+type YouTubeRecognizer struct{}
+
+var youTubeIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// RecognizeVideo implements VideoRecognizer
+func (*YouTubeRecognizer) RecognizeVideo(link *url.URL) *Video {
+	host := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(link.Hostname()), "www."), "m.")
+	segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+
+	id := ""
+	switch host {
+	case "youtu.be":
+		id = segments[0]
+	case "youtube.com", "youtube-nocookie.com":
+		switch {
+		case segments[0] == "watch":
+			id = link.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "embed" || segments[0] == "v" || segments[0] == "shorts" || segments[0] == "live"):
+			id = segments[1]
+		}
+	}
+	if !youTubeIDRegexp.MatchString(id) {
+		return nil
+	}
+
+	v := &Video{
+		Provider: "youtube",
+		ID:       id,
+		Start:    parseVideoStart(link, "t", "start"),
+	}
+	v.Link = "https://www.youtube.com/watch?v=" + id
+	if v.Start > 0 {
+		v.Link += fmt.Sprintf("&t=%ds", v.Start)
+	}
+	return v
+}
+
+var _ VideoRecognizer = &VimeoRecognizer{}
+
+// VimeoRecognizer recognises Vimeo videos, linked to in any of the vimeo.com/{id}, vimeo.com/channels/{channel}/{id}
+// and player.vimeo.com/video/{id} forms
+type VimeoRecognizer struct{}
+
+var vimeoIDRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+// RecognizeVideo implements VideoRecognizer
+func (*VimeoRecognizer) RecognizeVideo(link *url.URL) *Video {
+	host := strings.TrimPrefix(strings.ToLower(link.Hostname()), "www.")
+	if host != "vimeo.com" && host != "player.vimeo.com" {
+		return nil
+	}
+	// The ID is the last numeric path segment, e.g. vimeo.com/channels/staffpicks/123456
+	segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+	id := segments[len(segments)-1]
+	if !vimeoIDRegexp.MatchString(id) {
+		return nil
+	}
+
+	v := &Video{
+		Provider: "vimeo",
+		ID:       id,
+		Start:    parseVideoStart(link, "t"),
+	}
+	v.Link = "https://vimeo.com/" + id
+	if v.Start > 0 {
+		v.Link += fmt.Sprintf("#t=%ds", v.Start)
+	}
+	return v
+}
+
+var _ VideoRecognizer = &HostRecognizer{}
+
+// HostRecognizer recognises all links to the given hosts as videos, e.g. for a conference's own video
+// archive. The ID of the video is the path of the link, and the link is kept as-is.
+type HostRecognizer struct {
+	// Provider is the name of the video provider set in the recognised videos
+	Provider string
+
+	// Hosts are the hosts, e.g. "media.ccc.de", whose links are videos. Subdomains are not included
+	Hosts []string
+}
+
+// RecognizeVideo implements VideoRecognizer
+func (r *HostRecognizer) RecognizeVideo(link *url.URL) *Video {
+	for _, host := range r.Hosts {
+		if strings.EqualFold(link.Hostname(), host) && len(strings.Trim(link.Path, "/")) > 0 {
+			return &Video{
+				Provider: r.Provider,
+				ID:       strings.Trim(link.Path, "/"),
+				Link:     link.String(),
+			}
+		}
+	}
+	return nil
+}
+
+// parseVideoStart returns the start timestamp in seconds given by the first of the query parameters,
+// or the same parameters in the fragment of link, e.g. "#t=1m30s". Zero is returned if there's none.
+func parseVideoStart(link *url.URL, params ...string) int {
+	fragment, _ := url.ParseQuery(link.Fragment)
+	query := link.Query()
+	for _, param := range params {
+		for _, values := range []url.Values{query, fragment} {
+			if start := parseTimestamp(values.Get(param)); start > 0 {
+				return start
+			}
+		}
+	}
+	return 0
+}
+
+// parseTimestamp parses timestamps like "90", "90s" and "1h2m3s" into seconds. Zero is returned for
+// invalid timestamps.
+func parseTimestamp(ts string) int {
+	if len(ts) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(ts); err == nil && seconds > 0 {
+		return seconds
+	}
+	d, err := time.ParseDuration(ts)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return int(d.Seconds())
+}
@@ -0,0 +1,96 @@
+package speakerdeck
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRecognizeVideo(t *testing.T) {
+	ccc := &HostRecognizer{Provider: "media.ccc.de", Hosts: []string{"media.ccc.de"}}
+	recognizers := append(append([]VideoRecognizer{}, DefaultVideoRecognizers...), ccc)
+	tests := []struct {
+		link string
+		want *Video
+	}{
+		{
+			link: "https://youtu.be/dQw4w9WgXcQ",
+			want: &Video{Provider: "youtube", ID: "dQw4w9WgXcQ", Link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=90",
+			want: &Video{Provider: "youtube", ID: "dQw4w9WgXcQ", Start: 90, Link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=90s"},
+		},
+		{
+			link: "https://m.youtube.com/embed/dQw4w9WgXcQ?start=1m30s",
+			want: &Video{Provider: "youtube", ID: "dQw4w9WgXcQ", Start: 90, Link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=90s"},
+		},
+		{
+			link: "https://youtube.com/shorts/dQw4w9WgXcQ",
+			want: &Video{Provider: "youtube", ID: "dQw4w9WgXcQ", Link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			link: "https://www.youtube.com/channel/UCdQw4w9WgXcQ",
+			want: nil,
+		},
+		{
+			link: "https://youtu.be/tooshort",
+			want: nil,
+		},
+		{
+			link: "https://vimeo.com/123456789",
+			want: &Video{Provider: "vimeo", ID: "123456789", Link: "https://vimeo.com/123456789"},
+		},
+		{
+			link: "https://vimeo.com/channels/cloudnative/123456789#t=1m30s",
+			want: &Video{Provider: "vimeo", ID: "123456789", Start: 90, Link: "https://vimeo.com/123456789#t=90s"},
+		},
+		{
+			link: "https://player.vimeo.com/video/123456789",
+			want: &Video{Provider: "vimeo", ID: "123456789", Link: "https://vimeo.com/123456789"},
+		},
+		{
+			link: "https://vimeo.com/about",
+			want: nil,
+		},
+		{
+			link: "https://media.ccc.de/v/36c3-10000-kubernetes",
+			want: &Video{Provider: "media.ccc.de", ID: "v/36c3-10000-kubernetes", Link: "https://media.ccc.de/v/36c3-10000-kubernetes"},
+		},
+		{
+			link: "https://media.ccc.de/",
+			want: nil,
+		},
+		{
+			link: "https://example.com/watch?v=dQw4w9WgXcQ",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			link, err := url.Parse(tt.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := recognizeVideo(recognizers, link); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recognizeVideo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := map[string]int{
+		"":       0,
+		"90":     90,
+		"90s":    90,
+		"1h2m3s": 3723,
+		"-5":     0,
+		"soon":   0,
+	}
+	for ts, want := range tests {
+		if got := parseTimestamp(ts); got != want {
+			t.Errorf("parseTimestamp(%q) = %d, want %d", ts, got, want)
+		}
+	}
+}